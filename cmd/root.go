@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/joostvdg/cmg/cmd/webserver"
+	"github.com/joostvdg/cmg/pkg/analysis"
 	"github.com/joostvdg/cmg/pkg/game"
 	"github.com/joostvdg/cmg/pkg/mapgen"
+	"github.com/joostvdg/cmg/pkg/render"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
 )
 
 var GenCount int
@@ -18,21 +25,92 @@ var MinScore int
 var MaxResourceScore int
 var MinResourceScore int
 var MaxOver300 int
-var GameType int
+var GameType string
+var Seed int64
+var Definitions string
+var NoAdjacentRed bool
+var NoAdjacentSameNumber bool
+var NoSameNumberPerResource bool
+var MaxFirstSeatAdvantage int
+var MaxSeatGap int
+var Players int
+var Strategy string
+var Numbers string
+var BestEffort bool
+var Lock string
+var VariableHarbors bool
+var Optimize bool
+var Objective string
+var Iterations int
+var Output string
+var MapOutput string
+var Games int
+var Turns int
+var Robber bool
+var ExportFormat string
+var ExportFile string
+var ExportURL string
+
+// defaultCodeURL the URL of the map of a game code on the public server, without the code
+const defaultCodeURL = "https://catan-map-generator.herokuapp.com/api/map/code/"
 
 func init() {
 	mapGenCmd.Flags().IntVar(&MaxScore, "max", game.DefaultGameRulesNormal.MaximumScore, "Maximum Probability score of 3 adjacent tiles")
 	mapGenCmd.Flags().IntVar(&MinScore, "min", game.DefaultGameRulesNormal.MinimumScore, "Minimum Probability score of 3 adjacent tiles")
 	mapGenCmd.Flags().IntVar(&MaxResourceScore, "maxResource", game.DefaultGameRulesNormal.MaximumResourceScore, "Maximum average Probability score for resources per tile")
 	mapGenCmd.Flags().IntVar(&MinResourceScore, "minResource", game.DefaultGameRulesNormal.MinimumResourceScore, "Minimum average Probability score for resources per tile")
-	mapGenCmd.Flags().IntVar(&GameType, "gameType", 0, "GameType, 0 = normal, 1 = large (5or6 players)")
+	mapGenCmd.Flags().StringVar(&GameType, "gameType", "0", "GameType, normal or 0, large or 1 (5or6 players), seafarers-<scenario> = Seafarers (new-shores, four-islands), or the key of a game definition")
+	mapGenCmd.Flags().StringVar(&Definitions, "definitions", "", "Directory with additional game definition files (.yaml, .yml or .json)")
 	mapGenCmd.Flags().IntVar(&MaxOver300, "max300", game.DefaultGameRulesNormal.MaxOver300, "Number times the probability score of 3 adjacent tiles can exceed 300")
+	mapGenCmd.Flags().BoolVar(&NoAdjacentRed, "noAdjacentRed", false, "Do not allow the red numbers (6 and 8) on neighbouring tiles")
+	mapGenCmd.Flags().BoolVar(&NoAdjacentSameNumber, "noAdjacentSameNumber", false, "Do not allow the same number on neighbouring tiles")
+	mapGenCmd.Flags().BoolVar(&NoSameNumberPerResource, "noSameNumberPerResource", false, "Do not allow the same number on more than one tile of a resource")
+	mapGenCmd.Flags().IntVar(&MaxFirstSeatAdvantage, "maxFirstSeatAdvantage", 0, "Maximum pips the first seat may get above the average of the other seats in a draft of the opening settlements, 0 = not checked")
+	mapGenCmd.Flags().IntVar(&MaxSeatGap, "maxSeatGap", 0, "Maximum pips the best seat may get above the worst seat in a draft of the opening settlements, 0 = not checked")
+	mapGenCmd.Flags().IntVar(&Players, "players", 0, "Number of players, 3 to 6, to draft the opening settlements for, 0 = the players of the game type")
+	mapGenCmd.Flags().StringVar(&MapOutput, "output", "console", "Output format of the map(s), console or svg")
 	mapGenCmd.Flags().IntVar(&GenCount, "count", 0, "Number of times to generate a map, only for loop")
 	mapGenCmd.Flags().BoolVar(&GenLoop, "loop", false, "Generate maps in a loop 'count' times, or just once")
 	mapGenCmd.Flags().BoolVar(&Verbose, "verbose", false, "Verbose logging")
+	mapGenCmd.Flags().Int64Var(&Seed, "seed", 0, "Seed for the random generation, the same seed and rules generate the same map(s), 0 = random seed")
+	mapGenCmd.Flags().BoolVar(&BestEffort, "bestEffort", false, "Print the map that comes closest to the rules, when no map satisfies them")
+	mapGenCmd.Flags().BoolVar(&VariableHarbors, "variableHarbors", false, "Place the harbors on any coastal edge, instead of on the fixed harbor positions of the game type")
+	mapGenCmd.Flags().StringVar(&Lock, "lock", "", "Tiles to keep in place, such as c2:6z6,a0:harbor=0, or a partial game code with ? for what is generated")
+	mapGenCmd.Flags().BoolVar(&Optimize, "optimize", false, "Optimize the generated map(s) for a more balanced board")
+	mapGenCmd.Flags().StringVar(&Objective, "objective", string(mapgen.ObjectiveIntersectionVariance), "What to minimize when optimizing, intersectionVariance, resourceSpread or maxTileGroup")
+	mapGenCmd.Flags().IntVar(&Iterations, "iterations", mapgen.DefaultOptimizationIterations, "Number of swaps to try when optimizing")
+	mapGenCmd.Flags().StringVar(&Strategy, "strategy", string(mapgen.StrategyRandom), "How to search for a valid map, random = generate whole maps until one is valid, backtracking = place tiles one by one and undo those that break a rule")
+	mapGenCmd.Flags().StringVar(&Numbers, "numbers", "random", "How to place the numbers, random, or spiral = in alphabetical order along a spiral from a corner, like the setup of the official rules")
+
+	validateCmd.Flags().StringVar(&Definitions, "definitions", "", "Directory with additional game definition files (.yaml, .yml or .json)")
+	validateCmd.Flags().BoolVar(&NoAdjacentRed, "noAdjacentRed", false, "Do not allow the red numbers (6 and 8) on neighbouring tiles")
+	validateCmd.Flags().BoolVar(&NoAdjacentSameNumber, "noAdjacentSameNumber", false, "Do not allow the same number on neighbouring tiles")
+	validateCmd.Flags().BoolVar(&NoSameNumberPerResource, "noSameNumberPerResource", false, "Do not allow the same number on more than one tile of a resource")
+	validateCmd.Flags().IntVar(&MaxFirstSeatAdvantage, "maxFirstSeatAdvantage", 0, "Maximum pips the first seat may get above the average of the other seats in a draft of the opening settlements, 0 = not checked")
+	validateCmd.Flags().IntVar(&MaxSeatGap, "maxSeatGap", 0, "Maximum pips the best seat may get above the worst seat in a draft of the opening settlements, 0 = not checked")
+	validateCmd.Flags().IntVar(&Players, "players", 0, "Number of players, 3 to 6, to draft the opening settlements for, 0 = the players of the game type")
+
+	analyzeCmd.Flags().StringVar(&Definitions, "definitions", "", "Directory with additional game definition files (.yaml, .yml or .json)")
+	analyzeCmd.Flags().StringVar(&Output, "output", "table", "Output format, table or json")
+
+	simulateCmd.Flags().StringVar(&Definitions, "definitions", "", "Directory with additional game definition files (.yaml, .yml or .json)")
+	simulateCmd.Flags().StringVar(&Output, "output", "table", "Output format, table or json")
+	simulateCmd.Flags().IntVar(&Games, "games", analysis.DefaultSimulationGames, "Number of games to simulate")
+	simulateCmd.Flags().IntVar(&Turns, "turns", analysis.DefaultSimulationTurns, "Number of turns, rolls of the dice, per game")
+	simulateCmd.Flags().BoolVar(&Robber, "robber", false, "Move the robber to another tile on a 7, that tile produces nothing while the robber is on it")
+	simulateCmd.Flags().Int64Var(&Seed, "seed", 0, "Seed for the dice, the same seed simulates the same games, 0 = random seed")
+
+	exportCmd.Flags().StringVar(&Definitions, "definitions", "", "Directory with additional game definition files (.yaml, .yml or .json)")
+	exportCmd.Flags().StringVar(&ExportFormat, "format", "pdf", "Export format, pdf (printable table sheet), png or svg")
+	exportCmd.Flags().StringVar(&ExportFile, "file", "", "File to write the export to, empty = standard output")
+	exportCmd.Flags().StringVar(&ExportURL, "url", defaultCodeURL, "URL the QR code on the table sheet links to, followed by the game code")
 
 	rootCmd.AddCommand(mapGenCmd)
 	rootCmd.AddCommand(webServerCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(simulateCmd)
+	rootCmd.AddCommand(exportCmd)
 }
 
 var mapGenCmd = &cobra.Command{
@@ -40,18 +118,263 @@ var mapGenCmd = &cobra.Command{
 	Short: "Will generate a map",
 	Long:  `Anything to do with generating a Catan map`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if Definitions != "" {
+			if err := game.LoadGameDefinitions(Definitions); err != nil {
+				log.Fatalf("Could not load game definitions: %v", err)
+			}
+		}
+		gameTypeKey, definedGame, ok := game.DefinedGames.Lookup(GameType)
+		if !ok {
+			log.Fatalf("Unknown game type: %v", GameType)
+		}
+		defaultRules := definedGame.Rules
 		rules := game.GameRules{
-			GameType:             GameType,
-			MinimumScore:         MinScore,
-			MaximumScore:         MaxScore,
-			MaxOver300:           MaxOver300,
-			MaximumResourceScore: MaxResourceScore,
-			MinimumResourceScore: MinResourceScore,
-		}
-		mapgen.GenerateMap(GenCount, GenLoop, Verbose, rules)
+			GameType:                  defaultRules.GameType,
+			GameTypeString:            gameTypeKey,
+			MinimumScore:              MinScore,
+			MaximumScore:              MaxScore,
+			MaxOver300:                MaxOver300,
+			MaximumResourceScore:      MaxResourceScore,
+			MinimumResourceScore:      MinResourceScore,
+			MaxSameLandscapePerRow:    defaultRules.MaxSameLandscapePerRow,
+			MaxSameLandscapePerColumn: defaultRules.MaxSameLandscapePerColumn,
+			AdjacentSame:              defaultRules.AdjacentSame,
+			NoAdjacentRed:             NoAdjacentRed || defaultRules.NoAdjacentRed,
+			NoAdjacentSameNumber:      NoAdjacentSameNumber || defaultRules.NoAdjacentSameNumber,
+			NoSameNumberPerResource:   NoSameNumberPerResource || defaultRules.NoSameNumberPerResource,
+			MaxFirstSeatAdvantage:     defaultRules.MaxFirstSeatAdvantage,
+			MaxSeatGap:                defaultRules.MaxSeatGap,
+			Players:                   defaultRules.Players,
+			VariableHarbors:           VariableHarbors || defaultRules.VariableHarbors,
+		}
+		applySeatRules(&rules)
+		strategy, ok := mapgen.ParseStrategy(Strategy)
+		if !ok {
+			log.Fatalf("Unknown strategy: %v", Strategy)
+		}
+		numbers, ok := mapgen.ParseNumberPlacement(Numbers)
+		if !ok {
+			log.Fatalf("Unknown number placement: %v", Numbers)
+		}
+		locks, err := game.ParseLocks(Lock, definedGame.GameType)
+		if err != nil {
+			log.Fatalf("Invalid locks: %v", err)
+		}
+		if err := mapgen.VerifyNumberLocks(numbers, locks); err != nil {
+			log.Fatalf("Invalid locks: %v", err)
+		}
+		options := mapgen.GenerationOptions{
+			Seed:       Seed,
+			Strategy:   strategy,
+			BestEffort: BestEffort,
+			Locks:      locks,
+			Numbers:    numbers,
+		}
+		if Optimize {
+			objective, ok := mapgen.ParseObjective(Objective)
+			if !ok {
+				log.Fatalf("Unknown objective: %v", Objective)
+			}
+			options.Optimization = &mapgen.OptimizationOptions{
+				Objective:  objective,
+				Iterations: Iterations,
+			}
+		}
+		var print func(board *game.Board)
+		switch MapOutput {
+		case "console":
+			print = definedGame.Print
+		case "svg":
+			print = func(board *game.Board) {
+				if err := render.WriteSVG(os.Stdout, board); err != nil {
+					log.Fatalf("Could not write the map as SVG: %v", err)
+				}
+			}
+		default:
+			log.Fatalf("Unknown output format: %v", MapOutput)
+		}
+		mapgen.GenerateMap(GenCount, GenLoop, Verbose, rules, options, print)
 	},
 }
 
+var validateCmd = &cobra.Command{
+	Use:   "validate <code>",
+	Short: "Validates the map of a game code",
+	Long:  `Validates the map of a game code against the default rules of its game type, and prints the result of every rule`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if Definitions != "" {
+			if err := game.LoadGameDefinitions(Definitions); err != nil {
+				log.Fatalf("Could not load game definitions: %v", err)
+			}
+		}
+		board, err := game.InflateGameFromCode(args[0])
+		if err != nil {
+			log.Fatalf("Could not inflate map from code %v: %v", args[0], err)
+		}
+		rules := game.DefaultGameRulesNormal
+		if key, ok := game.DefinedGames.KeyOf(board.GameType); ok {
+			rules = game.DefinedGames[key].Rules
+		}
+		rules.NoAdjacentRed = NoAdjacentRed || rules.NoAdjacentRed
+		rules.NoAdjacentSameNumber = NoAdjacentSameNumber || rules.NoAdjacentSameNumber
+		rules.NoSameNumberPerResource = NoSameNumberPerResource || rules.NoSameNumberPerResource
+		applySeatRules(&rules)
+
+		report := board.Validate(rules)
+		fmt.Printf("Game type: %v\n", board.GameType.Name)
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "RULE\tRESULT\tVALUE\tTHRESHOLD\tPOSITIONS\tMESSAGE")
+		for _, result := range report.Results {
+			outcome := "valid"
+			if !result.Valid {
+				outcome = "invalid"
+			}
+			fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\t%v\n", result.Rule, outcome, result.Value, result.Threshold, strings.Join(result.Positions, ","), result.Message)
+		}
+		writer.Flush()
+		if !report.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze <code>",
+	Short: "Analyzes how fair the map of a game code is",
+	Long:  `Analyzes the map of a game code, and prints the production per resource, the distribution of the intersection scores, the clustering of the red numbers, the usefulness of the harbors and the centrality of the desert`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if Definitions != "" {
+			if err := game.LoadGameDefinitions(Definitions); err != nil {
+				log.Fatalf("Could not load game definitions: %v", err)
+			}
+		}
+		board, err := game.InflateGameFromCode(args[0])
+		if err != nil {
+			log.Fatalf("Could not inflate map from code %v: %v", args[0], err)
+		}
+
+		result := analysis.Analyze(&board)
+		switch Output {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(result)
+		case "table":
+			err = result.WriteTable(os.Stdout)
+		default:
+			log.Fatalf("Unknown output format: %v", Output)
+		}
+		if err != nil {
+			log.Fatalf("Could not print the analysis: %v", err)
+		}
+	},
+}
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate <code>",
+	Short: "Simulates the resource production of the map of a game code",
+	Long:  `Rolls the dice for a number of games over the map of a game code, and prints the spread of the resources each resource, tile and intersection yields per game`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if Definitions != "" {
+			if err := game.LoadGameDefinitions(Definitions); err != nil {
+				log.Fatalf("Could not load game definitions: %v", err)
+			}
+		}
+		board, err := game.InflateGameFromCode(args[0])
+		if err != nil {
+			log.Fatalf("Could not inflate map from code %v: %v", args[0], err)
+		}
+
+		random, seed := mapgen.NewRandom(Seed)
+		options := analysis.SimulationOptions{
+			Games:  Games,
+			Turns:  Turns,
+			Robber: Robber,
+		}
+		result := analysis.Simulate(context.Background(), &board, options, random)
+		switch Output {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(struct {
+				analysis.Simulation
+				Seed int64
+			}{result, seed})
+		case "table":
+			fmt.Printf("Seed: %v\n", seed)
+			err = result.WriteTable(os.Stdout)
+		default:
+			log.Fatalf("Unknown output format: %v", Output)
+		}
+		if err != nil {
+			log.Fatalf("Could not print the simulation: %v", err)
+		}
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <code>",
+	Short: "Exports the map of a game code as a printable table sheet or an image",
+	Long:  `Exports the map of a game code as an A4 PDF table sheet, with the board, the game code, a QR code of its URL and a setup checklist, or as a PNG or SVG image of the board`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if Definitions != "" {
+			if err := game.LoadGameDefinitions(Definitions); err != nil {
+				log.Fatalf("Could not load game definitions: %v", err)
+			}
+		}
+		board, err := game.InflateGameFromCode(args[0])
+		if err != nil {
+			log.Fatalf("Could not inflate map from code %v: %v", args[0], err)
+		}
+
+		var write func(w io.Writer, board *game.Board) error
+		switch ExportFormat {
+		case "pdf":
+			write = func(w io.Writer, board *game.Board) error {
+				return render.WritePDF(w, board, ExportURL+args[0])
+			}
+		case "png":
+			write = render.WritePNG
+		case "svg":
+			write = render.WriteSVG
+		default:
+			log.Fatalf("Unknown export format: %v", ExportFormat)
+		}
+
+		out := os.Stdout
+		if ExportFile != "" {
+			out, err = os.Create(ExportFile)
+			if err != nil {
+				log.Fatalf("Could not create %v: %v", ExportFile, err)
+			}
+			defer out.Close()
+		}
+		if err := write(out, &board); err != nil {
+			log.Fatalf("Could not export the map: %v", err)
+		}
+	},
+}
+
+// applySeatRules overrides the rules for the draft of the opening settlements with the flags that are set
+func applySeatRules(rules *game.GameRules) {
+	if MaxFirstSeatAdvantage > 0 {
+		rules.MaxFirstSeatAdvantage = MaxFirstSeatAdvantage
+	}
+	if MaxSeatGap > 0 {
+		rules.MaxSeatGap = MaxSeatGap
+	}
+	if Players != 0 {
+		if Players < game.MinPlayers || Players > game.MaxPlayers {
+			log.Fatalf("Players must be between %d and %d: %v", game.MinPlayers, game.MaxPlayers, Players)
+		}
+		rules.Players = Players
+	}
+}
+
 var webServerCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Starts an http server",
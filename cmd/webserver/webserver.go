@@ -17,6 +17,7 @@ import (
 	"gopkg.in/segmentio/analytics-go.v3"
 
 	"github.com/joostvdg/cmg/cmd/context"
+	"github.com/joostvdg/cmg/pkg/game"
 	"github.com/joostvdg/cmg/pkg/webserver"
 )
 
@@ -27,6 +28,8 @@ const (
 	envLogLevel          = "LOG_LEVEL"
 	envRootPath          = "ROOT_PATH"
 	envAnalyticsEndpoint = "ANALYTICS_API_ENDPOINT"
+	envGameDefinitions   = "GAME_DEFINITIONS_DIR"
+	envGenerationTimeout = "GENERATION_TIMEOUT"
 
 	defaultRootPath       = "/"
 	defaultPort           = "8080"
@@ -43,6 +46,8 @@ const (
 // StartWebserver starts the Echo webserver
 // Retrieves environment variable PORT for the server port to listen on
 // Retrieves environment variable SENTRY_DSN for exporting Sentry.io events
+// Retrieves environment variable GAME_DEFINITIONS_DIR for additional game definition files
+// Retrieves environment variable GENERATION_TIMEOUT for the maximum duration of generating a map, such as 10s
 func StartWebserver() {
 	port, portOk := os.LookupEnv(envPort)
 	if !portOk {
@@ -93,6 +98,22 @@ func StartWebserver() {
 		}
 	}
 
+	gameDefinitions, gameDefinitionsOk := os.LookupEnv(envGameDefinitions)
+	if gameDefinitionsOk && gameDefinitions != "" {
+		if err := game.LoadGameDefinitions(gameDefinitions); err != nil {
+			log.Fatalf("Could not load game definitions: %v", err)
+		}
+	}
+
+	generationTimeout, generationTimeoutOk := os.LookupEnv(envGenerationTimeout)
+	if generationTimeoutOk && generationTimeout != "" {
+		timeout, err := time.ParseDuration(generationTimeout)
+		if err != nil {
+			log.Fatalf("Could not parse generation timeout: %v", err)
+		}
+		webserver.GenerationTimeout = timeout
+	}
+
 	cmgAnalyticsEndpoint, cmgAnalyticsEndpointOk := os.LookupEnv(envAnalyticsEndpoint)
 	if !cmgAnalyticsEndpointOk {
 		cmgAnalyticsEndpoint = ""
@@ -191,9 +212,19 @@ func StartWebserver() {
 
 	g.GET("api/map", webserver.GetMap)
 	g.GET("api/v1/map", webserver.GetMapViaCodeGeneration)
+	// the rules and options of a v2 map request are a JSON document, see model.MapRequest
+	g.POST("api/v2/map", webserver.PostMapV2)
 	g.GET("api/map/code", webserver.GetMapCode)
+	// a code with the .svg or .png suffix, such as api/map/code/<code>.png, returns the map as an image, .pdf as a table sheet
 	g.GET("api/map/code/:code", webserver.GetMapByCode)
+	g.GET("api/map/code/:code/analysis", webserver.GetMapAnalysis)
+	g.GET("api/map/code/:code/convert", webserver.ConvertMapCode)
+	g.GET("api/map/code/:code/simulate", webserver.SimulateMapByCode)
+	g.GET("api/map/code/:code/placements", webserver.GetMapPlacements)
+	g.GET("api/map/validate/:code", webserver.ValidateMapByCode)
+	g.GET("api/map/optimize", webserver.OptimizeMap)
 	g.GET("api/legend", webserver.GetMapLegend)
+	g.GET("api/gametypes", webserver.GetGameTypes)
 
 	// Start server
 	e.Logger.Fatal(e.Start(":" + port))
@@ -0,0 +1,306 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// histogramBucket the width of the score ranges the intersections are counted in
+const histogramBucket = 100
+
+// Analysis the fairness metrics of a board
+// The scores are the probability scores of the numbers, the chance of a number being rolled in thousandths
+type Analysis struct {
+	GameType      string
+	GameCode      string
+	Resources     []ResourceProduction
+	Intersections IntersectionDistribution
+	RedNumbers    RedNumberClustering
+	Harbors       []HarborUsefulness
+	Desert        DesertCentrality
+}
+
+// ResourceProduction how much of a resource the board produces
+// ExpectedPerRoll is the number of resource cards the tiles produce per roll of the dice, for a single settlement on each
+type ResourceProduction struct {
+	Resource        string
+	Tiles           int
+	Score           int
+	AverageScore    int
+	ExpectedPerRoll float64
+}
+
+// IntersectionDistribution how the scores of the intersections, the places to build, are distributed
+// The Histogram counts the intersections per range of 100 score, the first counts the scores from 0 up to 100
+type IntersectionDistribution struct {
+	Count     int
+	Min       int
+	Max       int
+	Mean      float64
+	Median    float64
+	StdDev    float64
+	Histogram []int
+}
+
+// RedNumberClustering how close the red numbers, the 6s and 8s, are to each other
+// NeighbouringPairs counts the pairs of red tiles that share an edge,
+// SharedIntersections the intersections that touch more than one red tile
+type RedNumberClustering struct {
+	Positions           []string
+	NeighbouringPairs   int
+	SharedIntersections int
+}
+
+// HarborUsefulness how useful a harbor is, by how good its intersections are and how much the board produces what it trades
+// ResourceShare is the part of the production of the board that the harbor trades, which is all of it for a 3:1 harbor
+type HarborUsefulness struct {
+	Position           string
+	Harbor             string
+	Resource           string
+	IntersectionScores []int
+	ResourceScore      int
+	ResourceShare      float64
+}
+
+// DesertCentrality how close the deserts are to the center of the board
+// Distance is the number of tiles from the center, Centrality is 1 in the center and 0 on the tile furthest away from it
+type DesertCentrality struct {
+	Positions   []string
+	Distance    []int
+	MaxDistance int
+	Centrality  float64
+}
+
+// regularResources the resources that are produced by the regular landscapes
+var regularResources = []*model.Resource{model.Lumber, model.Wool, model.Grain, model.Brick, model.Ore}
+
+// Analyze computes the fairness metrics of a board
+func Analyze(board *game.Board) Analysis {
+	intersections, _ := board.Graph()
+	resources := resourceProduction(board)
+	return Analysis{
+		GameType:      board.GameType.Name,
+		GameCode:      board.GetGameCode(false),
+		Resources:     resources,
+		Intersections: intersectionDistribution(intersections),
+		RedNumbers:    redNumberClustering(board, intersections),
+		Harbors:       harborUsefulness(board, intersections, resources),
+		Desert:        desertCentrality(board),
+	}
+}
+
+func resourceProduction(board *game.Board) []ResourceProduction {
+	resources := make([]ResourceProduction, 0, len(regularResources))
+	for _, resource := range regularResources {
+		production := ResourceProduction{Resource: resource.Name}
+		for _, position := range board.Positions() {
+			tile := board.Tile(position)
+			if tile.Landscape.Resource != *resource || tile.Number.Pips() == 0 {
+				continue
+			}
+			production.Tiles++
+			production.Score += tile.Number.Score
+			production.ExpectedPerRoll += float64(tile.Number.Pips()) / 36
+		}
+		if production.Tiles > 0 {
+			production.AverageScore = production.Score / production.Tiles
+		}
+		production.ExpectedPerRoll = round(production.ExpectedPerRoll)
+		resources = append(resources, production)
+	}
+	return resources
+}
+
+func intersectionDistribution(intersections []model.Intersection) IntersectionDistribution {
+	distribution := IntersectionDistribution{Count: len(intersections), Histogram: make([]int, 0)}
+	if len(intersections) == 0 {
+		return distribution
+	}
+
+	scores := make([]int, 0, len(intersections))
+	total := 0
+	for _, intersection := range intersections {
+		scores = append(scores, intersection.Score)
+		total += intersection.Score
+	}
+	sort.Ints(scores)
+	distribution.Min = scores[0]
+	distribution.Max = scores[len(scores)-1]
+	distribution.Mean = float64(total) / float64(len(scores))
+
+	middle := len(scores) / 2
+	distribution.Median = float64(scores[middle])
+	if len(scores)%2 == 0 {
+		distribution.Median = float64(scores[middle-1]+scores[middle]) / 2
+	}
+
+	variance := 0.0
+	for _, score := range scores {
+		variance += math.Pow(float64(score)-distribution.Mean, 2)
+	}
+	distribution.StdDev = round(math.Sqrt(variance / float64(len(scores))))
+	distribution.Mean = round(distribution.Mean)
+
+	distribution.Histogram = make([]int, distribution.Max/histogramBucket+1)
+	for _, score := range scores {
+		distribution.Histogram[score/histogramBucket]++
+	}
+	return distribution
+}
+
+func redNumberClustering(board *game.Board, intersections []model.Intersection) RedNumberClustering {
+	clustering := RedNumberClustering{Positions: make([]string, 0)}
+	for _, position := range board.Positions() {
+		if isRed(board.Tile(position).Number) {
+			clustering.Positions = append(clustering.Positions, position)
+		}
+	}
+	for i, positionA := range clustering.Positions {
+		for _, positionB := range clustering.Positions[i+1:] {
+			if board.GameType.Hexes[positionA].IsNeighbour(board.GameType.Hexes[positionB]) {
+				clustering.NeighbouringPairs++
+			}
+		}
+	}
+	for _, intersection := range intersections {
+		red := 0
+		for _, position := range intersection.Tiles {
+			if isRed(board.Tile(position).Number) {
+				red++
+			}
+		}
+		if red > 1 {
+			clustering.SharedIntersections++
+		}
+	}
+	return clustering
+}
+
+func isRed(number model.Number) bool {
+	return number.Number == model.Number6.Number || number.Number == model.Number8.Number
+}
+
+func harborUsefulness(board *game.Board, intersections []model.Intersection, resources []ResourceProduction) []HarborUsefulness {
+	totalScore := 0
+	for _, production := range resources {
+		totalScore += production.Score
+	}
+
+	harbors := make([]HarborUsefulness, 0, board.GameType.HarborCount)
+	for _, position := range board.Positions() {
+		harbor := board.Tile(position).Harbor
+		if harbor.Name == "" || harbor == *model.HarborNone {
+			continue
+		}
+		usefulness := HarborUsefulness{
+			Position:           position,
+			Harbor:             harbor.Name,
+			Resource:           harbor.Resource.Name,
+			IntersectionScores: make([]int, 0, 2),
+		}
+		for _, intersection := range intersections {
+			if intersection.Harbor != nil && *intersection.Harbor == harbor && contains(intersection.Tiles, position) {
+				usefulness.IntersectionScores = append(usefulness.IntersectionScores, intersection.Score)
+			}
+		}
+
+		usefulness.ResourceScore = totalScore
+		if harbor.Resource != *model.All {
+			usefulness.ResourceScore = 0
+			for _, production := range resources {
+				if production.Resource == harbor.Resource.Name {
+					usefulness.ResourceScore = production.Score
+				}
+			}
+		}
+		if totalScore > 0 {
+			usefulness.ResourceShare = round(float64(usefulness.ResourceScore) / float64(totalScore))
+		}
+		harbors = append(harbors, usefulness)
+	}
+	return harbors
+}
+
+func desertCentrality(board *game.Board) DesertCentrality {
+	centrality := DesertCentrality{Positions: make([]string, 0), Distance: make([]int, 0)}
+	center, ok := centerHex(board)
+	if !ok {
+		return centrality
+	}
+
+	for _, position := range board.Positions() {
+		tile := board.Tile(position)
+		if tile.Landscape.Code == model.Sea.Code {
+			continue
+		}
+		distance := center.Distance(board.GameType.Hexes[position])
+		if distance > centrality.MaxDistance {
+			centrality.MaxDistance = distance
+		}
+		if tile.Landscape == *model.Desert {
+			centrality.Positions = append(centrality.Positions, position)
+			centrality.Distance = append(centrality.Distance, distance)
+		}
+	}
+
+	if len(centrality.Distance) == 0 || centrality.MaxDistance == 0 {
+		return centrality
+	}
+	total := 0
+	for _, distance := range centrality.Distance {
+		total += distance
+	}
+	average := float64(total) / float64(len(centrality.Distance))
+	centrality.Centrality = round(1 - average/float64(centrality.MaxDistance))
+	return centrality
+}
+
+// centerHex returns the land tile closest to the center of all land tiles of the board
+func centerHex(board *game.Board) (model.Hex, bool) {
+	land := make([]model.Hex, 0, len(board.GameType.Hexes))
+	centerX, centerY := 0.0, 0.0
+	for _, position := range board.Positions() {
+		if board.Tile(position).Landscape.Code == model.Sea.Code {
+			continue
+		}
+		hex := board.GameType.Hexes[position]
+		x, y := hex.Center()
+		centerX += x
+		centerY += y
+		land = append(land, hex)
+	}
+	if len(land) == 0 {
+		return model.Hex{}, false
+	}
+	centerX /= float64(len(land))
+	centerY /= float64(len(land))
+
+	center := land[0]
+	closest := math.Inf(1)
+	for _, hex := range land {
+		x, y := hex.Center()
+		distance := (x-centerX)*(x-centerX) + (y-centerY)*(y-centerY)
+		if distance < closest-1e-9 {
+			center = hex
+			closest = distance
+		}
+	}
+	return center, true
+}
+
+func contains(list []string, value string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+// round rounds to three decimals, which is precise enough to compare boards
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
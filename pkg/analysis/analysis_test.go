@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/stretchr/testify/assert"
+)
+
+const normalGameCode = "2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0"
+
+func analyzeCode(t *testing.T, code string) Analysis {
+	board, err := game.InflateGameFromCode(code)
+	assert.NoError(t, err)
+	return Analyze(&board)
+}
+
+func TestAnalyzeResources(t *testing.T) {
+	analysis := analyzeCode(t, normalGameCode)
+	assert.Equal(t, "Normal", analysis.GameType)
+	assert.Equal(t, normalGameCode, analysis.GameCode)
+	assert.Len(t, analysis.Resources, 5)
+
+	tiles := 0
+	for _, production := range analysis.Resources {
+		tiles += production.Tiles
+	}
+	assert.Equal(t, 18, tiles)
+
+	ore := analysis.Resources[4]
+	assert.Equal(t, "Ore", ore.Resource)
+	assert.Equal(t, 3, ore.Tiles)
+	assert.Equal(t, 361, ore.Score)
+	assert.Equal(t, 120, ore.AverageScore)
+	assert.Equal(t, 0.361, ore.ExpectedPerRoll)
+}
+
+func TestAnalyzeIntersections(t *testing.T) {
+	intersections := analyzeCode(t, normalGameCode).Intersections
+	assert.Equal(t, 54, intersections.Count)
+	assert.Equal(t, 0, intersections.Min)
+	assert.Equal(t, 361, intersections.Max)
+	assert.Equal(t, 179.5, intersections.Median)
+	assert.Equal(t, []int{15, 17, 12, 10}, intersections.Histogram)
+
+	total := 0
+	for _, count := range intersections.Histogram {
+		total += count
+	}
+	assert.Equal(t, intersections.Count, total)
+}
+
+func TestAnalyzeRedNumbersHarborsAndDesert(t *testing.T) {
+	analysis := analyzeCode(t, normalGameCode)
+	assert.Equal(t, []string{"a1", "b0", "c2", "d3"}, analysis.RedNumbers.Positions)
+	assert.Equal(t, 0, analysis.RedNumbers.NeighbouringPairs)
+	assert.Equal(t, 0, analysis.RedNumbers.SharedIntersections)
+
+	assert.Len(t, analysis.Harbors, 9)
+	for _, harbor := range analysis.Harbors {
+		assert.Len(t, harbor.IntersectionScores, 2, harbor.Position)
+		if harbor.Resource == "All" {
+			assert.Equal(t, 1.0, harbor.ResourceShare, harbor.Position)
+		} else {
+			assert.Less(t, harbor.ResourceShare, 1.0, harbor.Position)
+		}
+	}
+
+	assert.Equal(t, []string{"e0"}, analysis.Desert.Positions)
+	assert.Equal(t, []int{2}, analysis.Desert.Distance)
+	assert.Equal(t, 2, analysis.Desert.MaxDistance)
+	assert.Equal(t, 0.0, analysis.Desert.Centrality)
+}
+
+func TestAnalyzeDesertInCenter(t *testing.T) {
+	// the same board, with the desert swapped with the tile in the center
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	center, desert := board.Tile("c2"), board.Tile("e0")
+	center.Landscape, desert.Landscape = desert.Landscape, center.Landscape
+	center.Number, desert.Number = desert.Number, center.Number
+
+	analysis := Analyze(&board)
+	assert.Equal(t, []string{"c2"}, analysis.Desert.Positions)
+	assert.Equal(t, []int{0}, analysis.Desert.Distance)
+	assert.Equal(t, 1.0, analysis.Desert.Centrality)
+}
+
+func TestWriteTable(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, analyzeCode(t, normalGameCode).WriteTable(&buffer))
+	table := buffer.String()
+	assert.Contains(t, table, "Game type: Normal")
+	assert.Contains(t, table, "RESOURCE")
+	assert.Contains(t, table, "2:1 Ore")
+	assert.Contains(t, table, "CENTRALITY")
+}
@@ -0,0 +1,226 @@
+package analysis
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+const (
+	// DefaultSimulationGames the number of games that are simulated, when none is given
+	DefaultSimulationGames = 1000
+	// DefaultSimulationTurns the number of turns, rolls of the dice, of a simulated game, when none is given
+	DefaultSimulationTurns = 60
+)
+
+// SimulationOptions how many games of how many turns to simulate
+// With Robber, a roll of 7 moves the robber to another tile, which then produces nothing until the robber moves on
+type SimulationOptions struct {
+	Games  int
+	Turns  int
+	Robber bool
+}
+
+// Simulation the yields of a board over the simulated games, the number of resource cards produced per game
+// The yield of a tile is what a single settlement on it gets, the yield of an intersection what a settlement on it gets
+type Simulation struct {
+	GameType      string
+	GameCode      string
+	Games         int
+	Turns         int
+	Robber        bool
+	Sevens        Band
+	Tiles         []TileYield
+	Resources     []ResourceYield
+	Intersections []IntersectionYield
+}
+
+// Band the spread of a yield over the simulated games, the mean and the percentiles
+type Band struct {
+	Mean float64
+	P10  int
+	P25  int
+	P50  int
+	P75  int
+	P90  int
+}
+
+// TileYield the yield of the tile at the position
+type TileYield struct {
+	Position string
+	Resource string
+	Number   int
+	Yield    Band
+}
+
+// ResourceYield the yield of all tiles of a resource
+type ResourceYield struct {
+	Resource string
+	Yield    Band
+}
+
+// IntersectionYield the yield of an intersection, by its ID in the graph of the board
+type IntersectionYield struct {
+	ID    int
+	Tiles []string
+	Yield Band
+}
+
+// Simulate rolls two dice for the turns of each game, and counts the resources every tile, resource and intersection yields
+// The same random source results in the same simulation, it stops early when the context is done
+func Simulate(ctx context.Context, board *game.Board, options SimulationOptions, random *rand.Rand) Simulation {
+	if options.Games <= 0 {
+		options.Games = DefaultSimulationGames
+	}
+	if options.Turns <= 0 {
+		options.Turns = DefaultSimulationTurns
+	}
+
+	// the numbers of the producing tiles are looked up once, as the rolls only compare against them
+	producing := make([]string, 0, len(board.Tiles))
+	numbers := make([]int, 0, len(board.Tiles))
+	robberStart := ""
+	for _, position := range board.Positions() {
+		tile := board.Tile(position)
+		if tile.Number.Pips() > 0 && tile.Landscape.Code != model.Sea.Code {
+			producing = append(producing, position)
+			numbers = append(numbers, tile.Number.Number)
+		}
+		if tile.Landscape == *model.Desert && robberStart == "" {
+			robberStart = position
+		}
+	}
+	intersections, _ := board.Graph()
+
+	tileYields := make([][]int, len(producing))
+	sevens := make([]int, 0, options.Games)
+	games := 0
+	for games < options.Games && ctx.Err() == nil {
+		yields := make([]int, len(producing))
+		robber := robberStart
+		rolledSevens := 0
+		for turn := 0; turn < options.Turns; turn++ {
+			roll := random.Intn(6) + random.Intn(6) + 2
+			if roll == 7 {
+				rolledSevens++
+				if options.Robber && len(producing) > 1 {
+					robber = moveRobber(random, producing, robber)
+				}
+				continue
+			}
+			for i, position := range producing {
+				if numbers[i] == roll && position != robber {
+					yields[i]++
+				}
+			}
+		}
+		for i, yield := range yields {
+			tileYields[i] = append(tileYields[i], yield)
+		}
+		sevens = append(sevens, rolledSevens)
+		games++
+	}
+
+	simulation := Simulation{
+		GameType:      board.GameType.Name,
+		GameCode:      board.GetGameCode(false),
+		Games:         games,
+		Turns:         options.Turns,
+		Robber:        options.Robber,
+		Sevens:        band(sevens),
+		Tiles:         make([]TileYield, 0, len(producing)),
+		Resources:     make([]ResourceYield, 0, len(regularResources)),
+		Intersections: make([]IntersectionYield, 0, len(intersections)),
+	}
+	tileIndex := make(map[string]int, len(producing))
+	for i, position := range producing {
+		tileIndex[position] = i
+		tile := board.Tile(position)
+		simulation.Tiles = append(simulation.Tiles, TileYield{
+			Position: position,
+			Resource: tile.Landscape.Resource.Name,
+			Number:   tile.Number.Number,
+			Yield:    band(tileYields[i]),
+		})
+	}
+	for _, resource := range regularResources {
+		tiles := make([]int, 0)
+		for i, position := range producing {
+			if board.Tile(position).Landscape.Resource == *resource {
+				tiles = append(tiles, i)
+			}
+		}
+		simulation.Resources = append(simulation.Resources, ResourceYield{
+			Resource: resource.Name,
+			Yield:    band(sumYields(tileYields, tiles, games)),
+		})
+	}
+	for _, intersection := range intersections {
+		tiles := make([]int, 0, len(intersection.Tiles))
+		for _, position := range intersection.Tiles {
+			if i, ok := tileIndex[position]; ok {
+				tiles = append(tiles, i)
+			}
+		}
+		simulation.Intersections = append(simulation.Intersections, IntersectionYield{
+			ID:    intersection.ID,
+			Tiles: intersection.Tiles,
+			Yield: band(sumYields(tileYields, tiles, games)),
+		})
+	}
+	return simulation
+}
+
+// moveRobber moves the robber to another producing tile, which the player that rolled the 7 picks, here at random
+func moveRobber(random *rand.Rand, producing []string, robber string) string {
+	for {
+		position := producing[random.Intn(len(producing))]
+		if position != robber {
+			return position
+		}
+	}
+}
+
+// sumYields returns the yield of the tiles at the indexes together, per game
+func sumYields(tileYields [][]int, tiles []int, games int) []int {
+	sums := make([]int, games)
+	for _, i := range tiles {
+		for played, yield := range tileYields[i] {
+			sums[played] += yield
+		}
+	}
+	return sums
+}
+
+// band returns the mean and the percentiles of the yields, a percentile is the nearest rank in the sorted yields
+func band(yields []int) Band {
+	if len(yields) == 0 {
+		return Band{}
+	}
+	sorted := make([]int, len(yields))
+	copy(sorted, yields)
+	sort.Ints(sorted)
+
+	total := 0
+	for _, yield := range sorted {
+		total += yield
+	}
+	percentile := func(p int) int {
+		rank := (p*len(sorted) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+	return Band{
+		Mean: round(float64(total) / float64(len(sorted))),
+		P10:  percentile(10),
+		P25:  percentile(25),
+		P50:  percentile(50),
+		P75:  percentile(75),
+		P90:  percentile(90),
+	}
+}
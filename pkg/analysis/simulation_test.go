@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func simulateCode(t *testing.T, options SimulationOptions, seed int64) Simulation {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	return Simulate(context.Background(), &board, options, rand.New(rand.NewSource(seed)))
+}
+
+func TestSimulateSameSeedSameSimulation(t *testing.T) {
+	options := SimulationOptions{Games: 50, Turns: 40, Robber: true}
+	assert.Equal(t, simulateCode(t, options, 7), simulateCode(t, options, 7))
+}
+
+func TestSimulateDefaults(t *testing.T) {
+	simulation := simulateCode(t, SimulationOptions{}, 1)
+	assert.Equal(t, DefaultSimulationGames, simulation.Games)
+	assert.Equal(t, DefaultSimulationTurns, simulation.Turns)
+	assert.Len(t, simulation.Tiles, 18)
+	assert.Len(t, simulation.Resources, 5)
+	assert.Len(t, simulation.Intersections, 54)
+}
+
+func TestSimulateYieldFollowsProbability(t *testing.T) {
+	options := SimulationOptions{Games: 2000, Turns: 72}
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	simulation := Simulate(context.Background(), &board, options, rand.New(rand.NewSource(3)))
+
+	for _, tile := range simulation.Tiles {
+		expected := float64(board.Tile(tile.Position).Number.Pips()) / 36 * float64(options.Turns)
+		assert.InDelta(t, expected, tile.Yield.Mean, 0.5, tile.Position)
+		assert.LessOrEqual(t, tile.Yield.P10, tile.Yield.P50, tile.Position)
+		assert.LessOrEqual(t, tile.Yield.P50, tile.Yield.P90, tile.Position)
+	}
+	assert.InDelta(t, 12, simulation.Sevens.Mean, 0.5)
+}
+
+func TestSimulateRobberLowersYield(t *testing.T) {
+	without := simulateCode(t, SimulationOptions{Games: 500, Turns: 60}, 5)
+	with := simulateCode(t, SimulationOptions{Games: 500, Turns: 60, Robber: true}, 5)
+
+	total := func(simulation Simulation) float64 {
+		sum := 0.0
+		for _, resource := range simulation.Resources {
+			sum += resource.Yield.Mean
+		}
+		return sum
+	}
+	assert.Less(t, total(with), total(without))
+}
+
+func TestSimulateStopsWhenCanceled(t *testing.T) {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	simulation := Simulate(ctx, &board, SimulationOptions{Games: 10}, rand.New(rand.NewSource(1)))
+	assert.Equal(t, 0, simulation.Games)
+	assert.Equal(t, Band{}, simulation.Tiles[0].Yield)
+}
+
+func TestBandPercentiles(t *testing.T) {
+	yields := []int{10, 1, 9, 2, 8, 3, 7, 4, 6, 5}
+	assert.Equal(t, Band{Mean: 5.5, P10: 1, P25: 3, P50: 5, P75: 8, P90: 9}, band(yields))
+}
+
+func TestWriteSimulationTable(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, simulateCode(t, SimulationOptions{Games: 10}, 1).WriteTable(&buffer))
+	table := buffer.String()
+	assert.Contains(t, table, "Games: 10, turns: 60, robber: false")
+	assert.Contains(t, table, "Sevens")
+	assert.Contains(t, table, "INTERSECTION")
+}
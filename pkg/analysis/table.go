@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteTable writes the metrics of the analysis as tables, one for each metric
+func (analysis Analysis) WriteTable(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(writer, "Game type: %v\n", analysis.GameType)
+	fmt.Fprintf(writer, "Game code: %v\n\n", analysis.GameCode)
+
+	fmt.Fprintln(writer, "RESOURCE\tTILES\tSCORE\tAVERAGE\tPER ROLL")
+	for _, production := range analysis.Resources {
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%.3f\n", production.Resource, production.Tiles, production.Score, production.AverageScore, production.ExpectedPerRoll)
+	}
+
+	intersections := analysis.Intersections
+	fmt.Fprintln(writer, "\nINTERSECTIONS\tMIN\tMAX\tMEAN\tMEDIAN\tSTDDEV\tHISTOGRAM")
+	fmt.Fprintf(writer, "%v\t%v\t%v\t%.1f\t%.1f\t%.1f\t%v\n", intersections.Count, intersections.Min, intersections.Max, intersections.Mean, intersections.Median, intersections.StdDev, joinInts(intersections.Histogram))
+
+	red := analysis.RedNumbers
+	fmt.Fprintln(writer, "\nRED NUMBERS\tNEIGHBOURING PAIRS\tSHARED INTERSECTIONS")
+	fmt.Fprintf(writer, "%v\t%v\t%v\n", strings.Join(red.Positions, ","), red.NeighbouringPairs, red.SharedIntersections)
+
+	fmt.Fprintln(writer, "\nHARBOR\tPOSITION\tINTERSECTION SCORES\tRESOURCE SCORE\tSHARE")
+	for _, harbor := range analysis.Harbors {
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%.3f\n", harbor.Harbor, harbor.Position, joinInts(harbor.IntersectionScores), harbor.ResourceScore, harbor.ResourceShare)
+	}
+
+	desert := analysis.Desert
+	fmt.Fprintln(writer, "\nDESERT\tDISTANCE\tMAX DISTANCE\tCENTRALITY")
+	fmt.Fprintf(writer, "%v\t%v\t%v\t%.3f\n", strings.Join(desert.Positions, ","), joinInts(desert.Distance), desert.MaxDistance, desert.Centrality)
+	return writer.Flush()
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprint(value))
+	}
+	return strings.Join(parts, ",")
+}
+
+// WriteTable writes the yields of the simulation as tables, for the resources, the tiles and the intersections
+func (simulation Simulation) WriteTable(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(writer, "Game type: %v\n", simulation.GameType)
+	fmt.Fprintf(writer, "Game code: %v\n", simulation.GameCode)
+	fmt.Fprintf(writer, "Games: %v, turns: %v, robber: %v\n\n", simulation.Games, simulation.Turns, simulation.Robber)
+
+	fmt.Fprintln(writer, "RESOURCE\tMEAN\tP10\tP25\tP50\tP75\tP90")
+	for _, resource := range simulation.Resources {
+		fmt.Fprintf(writer, "%v\t%v\n", resource.Resource, bandColumns(resource.Yield))
+	}
+	fmt.Fprintf(writer, "Sevens\t%v\n", bandColumns(simulation.Sevens))
+
+	fmt.Fprintln(writer, "\nTILE\tRESOURCE\tNUMBER\tMEAN\tP10\tP25\tP50\tP75\tP90")
+	for _, tile := range simulation.Tiles {
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\n", tile.Position, tile.Resource, tile.Number, bandColumns(tile.Yield))
+	}
+
+	fmt.Fprintln(writer, "\nINTERSECTION\tTILES\tMEAN\tP10\tP25\tP50\tP75\tP90")
+	for _, intersection := range simulation.Intersections {
+		fmt.Fprintf(writer, "%v\t%v\t%v\n", intersection.ID, strings.Join(intersection.Tiles, ","), bandColumns(intersection.Yield))
+	}
+	return writer.Flush()
+}
+
+func bandColumns(band Band) string {
+	return fmt.Sprintf("%.2f\t%v\t%v\t%v\t%v\t%v", band.Mean, band.P10, band.P25, band.P50, band.P75, band.P90)
+}
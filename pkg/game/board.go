@@ -12,53 +12,67 @@ import (
 )
 
 // Board the Catan game Board, contains the Tiles and how they are distributed on the Board
+// HarborDirections is only set for boards with variable harbors, the other boards have their harbors face away from the board
 type Board struct {
 	Tiles            []*model.Tile
 	Board            map[string][]*model.Tile
 	GameType         GameType
 	Harbors          map[string]*model.Harbor
+	HarborDirections HarborDirections
 	GameCode         string
-	WaitGroup        sync.WaitGroup
 	TotalGenerations int
+	Seed             int64
 }
 
 // IsValid wrapper function for encapsulating all the validations for the map
 func (b *Board) IsValid(rules GameRules, game GameType) bool {
-	start := time.Now()
+	return b.Validate(rules).Valid
+}
 
-	isValid := true
+// Validate runs all the Validations for the map, and reports the result of each of them
+// The validations run concurrently, each writes only its own result
+func (b *Board) Validate(rules GameRules) ValidationReport {
+	start := time.Now()
 	log.Debug("Validating map")
 
+	var waitGroup sync.WaitGroup
 	validationFunctions := Validations
-	for _, validationFunc := range validationFunctions {
-		b.WaitGroup.Add(1)
-		go func(validation ValidateBoard) {
-			defer b.WaitGroup.Done()
-			valid := validation(b, rules)
-			if !valid {
-				isValid = false
-			}
-		}(validationFunc)
+	results := make([]ValidationResult, len(validationFunctions))
+	for index, validationFunc := range validationFunctions {
+		waitGroup.Add(1)
+		go func(index int, validation ValidateBoard) {
+			defer waitGroup.Done()
+			results[index] = validation(b, rules)
+		}(index, validationFunc)
 	}
 	log.Debug("Wait for validations to finish")
-	b.WaitGroup.Wait()
+	waitGroup.Wait()
+
+	report := ValidationReport{Valid: true, Results: results}
+	for _, result := range results {
+		if !result.Valid {
+			report.Valid = false
+		}
+	}
 
 	t := time.Now()
 	elapsed := t.Sub(start)
 	log.WithFields(log.Fields{
-		"Valid":       isValid,
+		"Valid":       report.Valid,
 		"Validations": len(Validations),
 		"Duration":    elapsed,
 		"Rules":       rules,
 	}).Debug("Validated map")
-	return isValid
+	return report
 }
 
-func (b *Board) validateAdjectTileGroup(max int, min int, tileCodeA string, tileCodeB string, tileCodeC string) (bool, int) {
-	weightTileA, _ := strconv.Atoi(b.element(tileCodeA))
-	weightTileB, _ := strconv.Atoi(b.element(tileCodeB))
-	weightTileC, _ := strconv.Atoi(b.element(tileCodeC))
-	weightTotal := weightTileA + weightTileB + weightTileC
+func (b *Board) validateAdjectTileGroup(max int, min int, positionA string, positionB string, positionC string) (bool, int) {
+	weightTotal := 0
+	for _, position := range []string{positionA, positionB, positionC} {
+		if tile := b.tile(position); tile != nil {
+			weightTotal += tile.Number.Score
+		}
+	}
 	if weightTotal > max || weightTotal < min {
 		log.WithFields(log.Fields{
 			"Score":       weightTotal,
@@ -70,25 +84,69 @@ func (b *Board) validateAdjectTileGroup(max int, min int, tileCodeA string, tile
 	return true, weightTotal
 }
 
-func sameResource(tileCode string, harborResource model.Resource, board map[string][]*model.Tile) bool {
-	if tileCode == "" {
+func sameResource(position string, harborResource model.Resource, board map[string][]*model.Tile) bool {
+	column, row, ok := parsePosition(position)
+	if !ok || row >= len(board[column]) {
 		return false
 	}
-	runeCode := []rune(tileCode)
-	column := string(runeCode[0])
-	row, _ := strconv.Atoi(string(runeCode[1]))
 	return board[column][row].Landscape.Resource == harborResource
 }
 
+// Tile returns the tile at a position such as c2, or nil when the position is not on the board
+func (board *Board) Tile(position string) *model.Tile {
+	return board.tile(position)
+}
+
+// tile returns the tile at a position such as c2, or nil when the position is not on the board
+func (board *Board) tile(position string) *model.Tile {
+	column, row, ok := parsePosition(position)
+	if !ok || row >= len(board.Board[column]) {
+		return nil
+	}
+	return board.Board[column][row]
+}
+
+// placed returns whether every one of the positions has a tile, while a board is generated some may not have one yet
+func (board *Board) placed(positions ...string) bool {
+	for _, position := range positions {
+		if board.tile(position) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// complete returns whether every position on the board has a tile
+func (board *Board) complete() bool {
+	return board.placed(board.Positions()...)
+}
+
+// Positions returns the positions of all tiles on the board, ordered by column and row
+func (board *Board) Positions() []string {
+	columns := make([]string, 0, len(board.Board))
+	for column := range board.Board {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	positions := make([]string, 0, len(board.Tiles))
+	for _, column := range columns {
+		for row := range board.Board[column] {
+			positions = append(positions, fmt.Sprintf("%s%d", column, row))
+		}
+	}
+	return positions
+}
+
 func (b *Board) PrintToConsole() {
 	b.GameType.ToConsole(b)
 }
 
+// element returns an element of a tile as text, the code is the row, the column and the type of element, such as 0cn
 func (board *Board) element(code string) string {
-	runeCode := []rune(code)
-	row, _ := strconv.Atoi(string(runeCode[0]))
-	column := string(runeCode[1])
-	elementType := string(runeCode[2])
+	row, _ := strconv.Atoi(code[:len(code)-2])
+	column := code[len(code)-2 : len(code)-1]
+	elementType := code[len(code)-1:]
 	switch elementType {
 	case "l":
 		return fmt.Sprintf("%v", board.Board[column][row].Landscape)
@@ -127,6 +185,9 @@ func (board *Board) GetGameCode(delimiter bool) string {
 				code += "_"
 			}
 		}
+		if len(board.HarborDirections) > 0 {
+			code += harborDirectionSeparator + board.HarborDirections.code(board.Positions())
+		}
 		board.GameCode = code
 	}
 	return board.GameCode
@@ -0,0 +1,162 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// Graph returns the intersections and roads of the board, the places where players build
+// Every intersection and road that touches a land tile is included, ordered by their hex coordinates
+// A harbor gives access to the two intersections on the coastal edge of its tile that it faces
+func (b *Board) Graph() ([]model.Intersection, []model.Road) {
+	land := b.landHexes()
+
+	vertices := make(map[model.HexVertex]bool)
+	edges := make(map[model.HexEdge]bool)
+	for hex := range land {
+		for _, vertex := range hex.Vertices() {
+			vertices[vertex] = true
+		}
+		for _, edge := range hex.Edges() {
+			edges[edge] = true
+		}
+	}
+
+	harbors := make(map[model.HexEdge]*model.Harbor)
+	for position, edge := range b.HarborEdges() {
+		harbor := b.tile(position).Harbor
+		harbors[edge] = &harbor
+	}
+
+	sortedVertices := make([]model.HexVertex, 0, len(vertices))
+	for vertex := range vertices {
+		sortedVertices = append(sortedVertices, vertex)
+	}
+	sort.Slice(sortedVertices, func(i, j int) bool {
+		return sortedVertices[i].Less(sortedVertices[j])
+	})
+
+	intersections := make([]model.Intersection, 0, len(sortedVertices))
+	intersectionIds := make(map[model.HexVertex]int)
+	for id, vertex := range sortedVertices {
+		intersection := model.Intersection{
+			ID:        id,
+			Vertex:    vertex,
+			Tiles:     make([]string, 0, len(vertex)),
+			Resources: make(map[string]int),
+		}
+		for _, hex := range vertex {
+			position, ok := land[hex]
+			if !ok {
+				intersection.Coastal = true
+				continue
+			}
+			intersection.Tiles = append(intersection.Tiles, position)
+			tile := b.tile(position)
+			if tile.Number.Pips() > 0 {
+				intersection.Score += tile.Number.Score
+				intersection.Pips += tile.Number.Pips()
+				intersection.Resources[tile.Landscape.Resource.Name] += tile.Number.Pips()
+			}
+		}
+		for _, edge := range vertex.Edges() {
+			if harbor, ok := harbors[edge]; ok {
+				intersection.Harbor = harbor
+			}
+		}
+		intersectionIds[vertex] = id
+		intersections = append(intersections, intersection)
+	}
+
+	sortedEdges := make([]model.HexEdge, 0, len(edges))
+	for edge := range edges {
+		sortedEdges = append(sortedEdges, edge)
+	}
+	sort.Slice(sortedEdges, func(i, j int) bool {
+		return sortedEdges[i].Less(sortedEdges[j])
+	})
+
+	roads := make([]model.Road, 0, len(sortedEdges))
+	for id, edge := range sortedEdges {
+		road := model.Road{
+			ID:    id,
+			Edge:  edge,
+			Tiles: make([]string, 0, len(edge)),
+		}
+		for _, hex := range edge {
+			if position, ok := land[hex]; ok {
+				road.Tiles = append(road.Tiles, position)
+			} else {
+				road.Coastal = true
+			}
+		}
+		for i, vertex := range edge.Vertices() {
+			road.Intersections[i] = intersectionIds[vertex]
+		}
+		roads = append(roads, road)
+	}
+
+	return intersections, roads
+}
+
+// HarborEdges returns the coastal edge that each harbor faces, by the position of the land tile it is on
+// The edge is the one of the HarborDirections for boards with variable harbors
+func (b *Board) HarborEdges() map[string]model.HexEdge {
+	land := b.landHexes()
+	edges := make(map[string]model.HexEdge)
+	for hex, position := range land {
+		harbor := b.tile(position).Harbor
+		if harbor.Name == "" || harbor == *model.HarborNone {
+			continue
+		}
+		if direction, ok := b.HarborDirections[position]; ok {
+			edges[position] = model.NewHexEdge(hex, hex.Neighbour(direction))
+			continue
+		}
+		if edge, ok := b.harborEdge(hex, land); ok {
+			edges[position] = edge
+		}
+	}
+	return edges
+}
+
+// landHexes returns the positions of the land tiles on the board by their hex coordinates
+func (b *Board) landHexes() map[model.Hex]string {
+	land := make(map[model.Hex]string)
+	for position, hex := range b.GameType.Hexes {
+		tile := b.tile(position)
+		if tile != nil && tile.Landscape.Code != model.Sea.Code {
+			land[hex] = position
+		}
+	}
+	return land
+}
+
+// harborEdge returns the coastal edge of a tile that its harbor faces
+// A harbor faces away from the board, to the coastal edge that lies furthest from the center of the board
+func (b *Board) harborEdge(hex model.Hex, land map[model.Hex]string) (model.HexEdge, bool) {
+	centerX, centerY := 0.0, 0.0
+	for _, boardHex := range b.GameType.Hexes {
+		x, y := boardHex.Center()
+		centerX += x / float64(len(b.GameType.Hexes))
+		centerY += y / float64(len(b.GameType.Hexes))
+	}
+
+	var harborEdge model.HexEdge
+	found := false
+	furthest := 0.0
+	for _, neighbour := range hex.Neighbours() {
+		if _, ok := land[neighbour]; ok {
+			continue
+		}
+		x, y := neighbour.Center()
+		distance := (x-centerX)*(x-centerX) + (y-centerY)*(y-centerY)
+		if !found || distance > furthest+1e-9 {
+			harborEdge = model.NewHexEdge(hex, neighbour)
+			furthest = distance
+			found = true
+		}
+	}
+	return harborEdge, found
+}
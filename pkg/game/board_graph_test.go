@@ -0,0 +1,92 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// a normal game with the harbors on the standard harbor slots, with a 2:1 Brick harbor on c0
+const graphTestCode = "2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0"
+
+func TestGraphNormalGame(t *testing.T) {
+	gameType := NormalGame
+	board, err := InflateNormalGameFromCode(graphTestCode, &gameType)
+	assert.NoError(t, err)
+
+	intersections, roads := board.Graph()
+	assert.Equal(t, 54, len(intersections))
+	assert.Equal(t, 72, len(roads))
+
+	inland := 0
+	withHarbor := 0
+	withBrickHarbor := 0
+	for id, intersection := range intersections {
+		assert.Equal(t, id, intersection.ID)
+		if len(intersection.Tiles) == 3 {
+			inland++
+			assert.False(t, intersection.Coastal)
+		}
+		if intersection.Harbor != nil {
+			withHarbor++
+			assert.True(t, intersection.Coastal)
+			if *intersection.Harbor == *model.HarborBrick {
+				assert.Equal(t, []string{"c0"}, intersection.Tiles)
+				withBrickHarbor++
+			}
+		}
+	}
+	assert.Equal(t, len(gameType.AdjacentTileGroups), inland)
+	assert.Equal(t, 2*gameType.HarborCount, withHarbor)
+	assert.Equal(t, 2, withBrickHarbor)
+
+	for _, road := range roads {
+		for _, id := range road.Intersections {
+			assert.Contains(t, intersections[id].Vertex, road.Edge[0])
+			assert.Contains(t, intersections[id].Vertex, road.Edge[1])
+		}
+	}
+}
+
+func TestGraphIntersectionScores(t *testing.T) {
+	gameType := NormalGame
+	board, err := InflateNormalGameFromCode(graphTestCode, &gameType)
+	assert.NoError(t, err)
+
+	intersections, _ := board.Graph()
+	for _, intersection := range intersections {
+		switch {
+		case assert.ObjectsAreEqual([]string{"a0", "a1", "b1"}, intersection.Tiles):
+			// 12 on Wool, 8 on Ore and 3 on Lumber
+			assert.Equal(t, 1+5+2, intersection.Pips)
+			assert.Equal(t, 27+139+55, intersection.Score)
+			assert.Equal(t, map[string]int{"Wool": 1, "Ore": 5, "Lumber": 2}, intersection.Resources)
+		case assert.ObjectsAreEqual([]string{"e0"}, intersection.Tiles):
+			// the desert does not produce
+			assert.Equal(t, 0, intersection.Pips)
+			assert.Empty(t, intersection.Resources)
+		}
+	}
+}
+
+func TestGraphSeafarersSkipsSea(t *testing.T) {
+	gameType := SeafarersGames[SeafarersGameTypePrefix+"new-shores"]
+	board := Board{Board: make(map[string][]*model.Tile), GameType: gameType}
+	for column, tilesInColumn := range gameType.BoardLayout {
+		for row := 0; row < tilesInColumn; row++ {
+			board.Board[column] = append(board.Board[column], &model.Tile{Landscape: *model.Forest, Number: *model.Number5, Harbor: *model.HarborNone})
+		}
+	}
+	for _, position := range gameType.SeaLayout {
+		board.tile(position).Landscape = *model.Sea
+	}
+
+	intersections, _ := board.Graph()
+	for _, intersection := range intersections {
+		assert.NotEmpty(t, intersection.Tiles)
+		for _, position := range intersection.Tiles {
+			assert.NotContains(t, gameType.SeaLayout, position)
+		}
+	}
+}
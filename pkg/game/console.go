@@ -0,0 +1,80 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+const (
+	columnsEmptyTemplate  string = "......"
+	columnsTileTemplate   string = ".%s%s."
+	columnsHarborTemplate string = ".H%-3s."
+	// columnsDirectedHarborTemplate a harbor with the direction of the coast it faces, for boards with variable harbors
+	columnsDirectedHarborTemplate string = "%-2s%-3s."
+)
+
+// harborDirectionNames the compass directions of the model.HexDirections, as printed in front of a harbor
+var harborDirectionNames = [6]string{"SE", "NE", "N", "NW", "SW", "S"}
+
+// printColumnsToConsole prints any game board to the console, used by game types without a dedicated layout
+// Every column is printed top to bottom, each tile takes two lines: the landscape with its number, and its harbor
+// Columns with fewer tiles start half a tile lower, like on the board
+// On boards with variable harbors, a harbor is printed with the direction of the coast it faces, such as NWOre
+func printColumnsToConsole(b *Board) {
+	columns := make([]string, 0, len(b.GameType.BoardLayout))
+	maxTilesInColumn := 0
+	for column, tilesInColumn := range b.GameType.BoardLayout {
+		columns = append(columns, column)
+		if tilesInColumn > maxTilesInColumn {
+			maxTilesInColumn = tilesInColumn
+		}
+	}
+	sort.Strings(columns)
+
+	for line := 0; line < maxTilesInColumn*2; line++ {
+		var sb strings.Builder
+		for _, column := range columns {
+			offset := maxTilesInColumn - b.GameType.BoardLayout[column]
+			index := (line - offset) / 2
+			if line < offset || index >= len(b.Board[column]) {
+				sb.WriteString(columnsEmptyTemplate)
+				continue
+			}
+
+			tile := b.Board[column][index]
+			if (line-offset)%2 == 0 {
+				sb.WriteString(consoleTile(tile))
+			} else if direction, ok := b.HarborDirections[fmt.Sprintf("%s%d", column, index)]; ok && tile.Harbor != *model.HarborNone {
+				harbor := fmt.Sprintf(columnsDirectedHarborTemplate, harborDirectionNames[direction], consoleHarbor(tile.Harbor))
+				sb.WriteString(strings.ReplaceAll(harbor, " ", "."))
+			} else if tile.Harbor.Name != "" && tile.Harbor != *model.HarborNone {
+				sb.WriteString(fmt.Sprintf(columnsHarborTemplate, consoleHarbor(tile.Harbor)))
+			} else {
+				sb.WriteString(columnsEmptyTemplate)
+			}
+		}
+		fmt.Println(sb.String())
+	}
+}
+
+func consoleTile(tile *model.Tile) string {
+	if tile.Landscape.Code == model.Sea.Code {
+		return fmt.Sprintf(columnsTileTemplate, "~~", "~~")
+	}
+	number := ".."
+	if tile.Number.Number > 0 {
+		number = fmt.Sprintf("%2d", tile.Number.Number)
+		number = strings.Replace(number, " ", ".", 1)
+	}
+	return fmt.Sprintf(columnsTileTemplate, tile.Landscape.Name[0:2], number)
+}
+
+func consoleHarbor(harbor model.Harbor) string {
+	if harbor.Resource == *model.All {
+		return harbor.Name
+	}
+	return harbor.Resource.Name[0:3]
+}
@@ -0,0 +1,192 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	"github.com/kennygrant/sanitize"
+)
+
+// CodeFormat the format of a game code
+// A v1 code has three characters per tile, for its landscape, number and harbor, and is recognized by its length.
+// A v2 code starts with the code tag of its game type and the format version, such as N2- for the Normal game,
+// followed by two base32 characters per tile and a checksum of four characters
+// Boards with variable harbors have the directions of their harbors after the tiles, in a v1 code after a ~,
+// in a v2 code as one character per harbor before the checksum
+type CodeFormat string
+
+const (
+	CodeFormatV1 CodeFormat = "v1"
+	CodeFormatV2 CodeFormat = "v2"
+)
+
+// CodeFormats the formats of game codes, from the oldest to the newest
+var CodeFormats = []CodeFormat{CodeFormatV1, CodeFormatV2}
+
+// ErrInvalidChecksum the checksum of a v2 game code does not match the rest of the code, which usually is a typo
+var ErrInvalidChecksum = errors.New("invalid game code checksum")
+
+const (
+	// codeVersion the format version in the prefix of v2 codes
+	codeVersion = "2"
+	// codePrefixSeparator separates the prefix of a v2 code from its body, v1 codes never contain it
+	codePrefixSeparator = "-"
+	// checksumLength the number of base32 characters of the checksum, 20 bits of a CRC-32
+	checksumLength = 4
+	// base32Alphabet the Crockford alphabet, without the I, L, O and U that are easily mistaken for other characters
+	base32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// numberCodes the codes of the numbers, in the order of their value in a v2 code
+	numberCodes = "zabcdefghij"
+)
+
+// ParseCodeFormat returns the code format for its name, such as v2, and whether it exists
+func ParseCodeFormat(value string) (CodeFormat, bool) {
+	for _, format := range CodeFormats {
+		if strings.EqualFold(value, string(format)) {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// GameCodeFormat returns the format of the game code, by its prefix, without checking the rest of the code
+func GameCodeFormat(code string) CodeFormat {
+	if _, _, _, ok := splitCodeV2(code); ok {
+		return CodeFormatV2
+	}
+	return CodeFormatV1
+}
+
+// GetGameCodeV2 returns the v2 game code of the board
+func (board *Board) GetGameCodeV2() (string, error) {
+	if board.GameType.CodeTag == "" {
+		return "", fmt.Errorf("game type %s has no code tag for v2 game codes", board.GameType.Name)
+	}
+	v1, directions := splitHarborDirections(strings.ReplaceAll(board.GetGameCode(false), DefaultGameRulesNormal.Delimiter, ""))
+
+	var body strings.Builder
+	for i := 0; i+3 <= len(v1); i += 3 {
+		landscape := int(v1[i] - '1')
+		number := strings.IndexByte(numberCodes, v1[i+1])
+		harbor := int(v1[i+2] - '0')
+		if landscape < 0 || landscape > 7 || number < 0 || harbor < 0 || harbor > 7 {
+			return "", fmt.Errorf("tile %s can not be written in a v2 game code", v1[i:i+3])
+		}
+		value := landscape<<7 | number<<3 | harbor
+		body.WriteByte(base32Alphabet[value>>5])
+		body.WriteByte(base32Alphabet[value&31])
+	}
+	body.WriteString(directions)
+	prefix := board.GameType.CodeTag + codeVersion
+	return prefix + codePrefixSeparator + body.String() + codeChecksum(prefix, body.String()), nil
+}
+
+// InflateGameFromCodeV2 inflates a board from a v2 game code, of the game type with the code tag in its prefix
+// Lower case letters are read as capitals, and the I, L and O as the digits they resemble
+// Returns ErrUnrecognizableCode when no game type has the code tag, and ErrInvalidChecksum when the checksum does not match
+func InflateGameFromCodeV2(code string) (Board, error) {
+	codeTag, version, rest, ok := splitCodeV2(code)
+	if !ok || version != codeVersion {
+		return Board{}, ErrUnrecognizableCode
+	}
+	key, ok := DefinedGames.KeyByCodeTag(codeTag)
+	if !ok {
+		return Board{}, ErrUnrecognizableCode
+	}
+	gameType := DefinedGames[key].GameType
+
+	rest = normalizeBase32(rest)
+	if len(rest) < gameType.TilesCount*2+checksumLength {
+		return Board{}, fmt.Errorf("Inflation error: a v2 code of %s has %d characters after the prefix, not %d",
+			gameType.Name, len(rest), gameType.TilesCount*2+checksumLength)
+	}
+	body, checksum := rest[:len(rest)-checksumLength], rest[len(rest)-checksumLength:]
+	if codeChecksum(codeTag+version, body) != checksum {
+		return Board{}, ErrInvalidChecksum
+	}
+	body, directions := body[:gameType.TilesCount*2], body[gameType.TilesCount*2:]
+
+	var v1 strings.Builder
+	for i := 0; i < len(body); i += 2 {
+		high := strings.IndexByte(base32Alphabet, body[i])
+		low := strings.IndexByte(base32Alphabet, body[i+1])
+		if high < 0 || low < 0 {
+			return Board{}, fmt.Errorf("Inflation error: %s is not a valid v2 code for a tile", sanitize.Name(body[i:i+2]))
+		}
+		value := high<<5 | low
+		number := value >> 3 & 15
+		if number >= len(numberCodes) {
+			return Board{}, fmt.Errorf("Inflation error: %s is not a valid v2 code for a tile", sanitize.Name(body[i:i+2]))
+		}
+		v1.WriteString(strconv.Itoa(value>>7 + 1))
+		v1.WriteByte(numberCodes[number])
+		v1.WriteString(strconv.Itoa(value & 7))
+	}
+	board, err := inflateGameFromCode(v1.String(), gameType.BoardLayout, &gameType)
+	if err != nil {
+		return Board{}, err
+	}
+	if err := board.applyHarborDirections(directions); err != nil {
+		return Board{}, err
+	}
+	return board, nil
+}
+
+// ConvertGameCode converts a game code of either format into the format
+func ConvertGameCode(code string, format CodeFormat) (string, error) {
+	board, err := InflateGameFromCode(code)
+	if err != nil {
+		return "", err
+	}
+	return board.GetGameCodeInFormat(format)
+}
+
+// GetGameCodeInFormat returns the game code of the board in the format
+func (board *Board) GetGameCodeInFormat(format CodeFormat) (string, error) {
+	switch format {
+	case CodeFormatV1:
+		return board.GetGameCode(false), nil
+	case CodeFormatV2:
+		return board.GetGameCodeV2()
+	default:
+		return "", fmt.Errorf("unknown game code format %s", sanitize.Name(string(format)))
+	}
+}
+
+// splitCodeV2 splits a v2 code into the code tag and version of its prefix, in capitals, and the rest of the code
+func splitCodeV2(code string) (string, string, string, bool) {
+	prefix, rest, found := strings.Cut(code, codePrefixSeparator)
+	if !found {
+		return "", "", "", false
+	}
+	prefix = strings.ToUpper(prefix)
+	digits := strings.IndexAny(prefix, "0123456789")
+	if digits < 1 {
+		return "", "", "", false
+	}
+	for i, character := range prefix {
+		if (i < digits && (character < 'A' || character > 'Z')) || (i >= digits && (character < '0' || character > '9')) {
+			return "", "", "", false
+		}
+	}
+	return prefix[:digits], prefix[digits:], rest, true
+}
+
+// normalizeBase32 reads lower case letters as capitals, and the letters that are not in the alphabet as the digits they resemble
+func normalizeBase32(value string) string {
+	return strings.NewReplacer("I", "1", "L", "1", "O", "0").Replace(strings.ToUpper(value))
+}
+
+// codeChecksum the last 20 bits of the CRC-32 of the prefix and the body, in base32
+func codeChecksum(prefix string, body string) string {
+	sum := crc32.ChecksumIEEE([]byte(prefix + codePrefixSeparator + body))
+	checksum := make([]byte, checksumLength)
+	for i := checksumLength - 1; i >= 0; i-- {
+		checksum[i] = base32Alphabet[sum&31]
+		sum >>= 5
+	}
+	return string(checksum)
+}
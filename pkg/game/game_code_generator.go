@@ -0,0 +1,183 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// GenerateGameCode generates a v1 game code for any game type, by shuffling the landscapes, numbers and harbors of the game type
+// with the given random source
+// The pools are in the order of their codes before they are shuffled, so the same random source always results in the same code
+// The positions of the Sea tiles and the harbors are those of the game type, every land tile except the deserts gets a number
+func GenerateGameCode(gameType GameType, random *rand.Rand) string {
+	return GenerateLockedGameCode(gameType, nil, random)
+}
+
+// GenerateLockedGameCode generates a v1 game code like GenerateGameCode, with the locked elements on their positions
+// Only the elements that are not locked are shuffled, the locks are expected to be verified by ParseLocks
+func GenerateLockedGameCode(gameType GameType, locks Locks, random *rand.Rand) string {
+	start := time.Now()
+	landscapePool, _ := withoutCodes(landscapeCodePool(gameType), locks.codes(func(lock TileLock) string { return lock.Landscape }))
+	numberPool, _ := withoutCodes(numberCodePool(gameType), locks.codes(func(lock TileLock) string {
+		if lock.Number == model.NumberEmpty.Code {
+			return ""
+		}
+		return lock.Number
+	}))
+	harborPool, _ := withoutCodes(harborCodePool(gameType), locks.codes(func(lock TileLock) string { return lock.Harbor }))
+	random.Shuffle(len(landscapePool), func(i, j int) {
+		landscapePool[i], landscapePool[j] = landscapePool[j], landscapePool[i]
+	})
+	random.Shuffle(len(numberPool), func(i, j int) {
+		numberPool[i], numberPool[j] = numberPool[j], numberPool[i]
+	})
+	random.Shuffle(len(harborPool), func(i, j int) {
+		harborPool[i], harborPool[j] = harborPool[j], harborPool[i]
+	})
+
+	harborPositions := make(map[string]bool)
+	for _, position := range gameType.HarborLayout {
+		harborPositions[position] = true
+	}
+	seaPositions := make(map[string]bool)
+	for _, position := range gameType.SeaLayout {
+		seaPositions[position] = true
+	}
+
+	positions := codePositions(gameType)
+	landscapes := make(map[string]string, len(positions))
+	shuffled := make([]string, 0, len(landscapePool))
+	for _, position := range positions {
+		if seaPositions[position] {
+			continue
+		}
+		if landscape := locks[position].Landscape; landscape != "" {
+			landscapes[position] = landscape
+			continue
+		}
+		landscapes[position] = landscapePool[len(shuffled)]
+		shuffled = append(shuffled, position)
+	}
+	// a desert can not be on a position with a locked number, it trades places with the first landscape that can
+	for _, position := range shuffled {
+		if landscapes[position] != model.Desert.Code || locks[position].Number == "" {
+			continue
+		}
+		for _, other := range shuffled {
+			if landscapes[other] != model.Desert.Code && locks[other].Number == "" {
+				landscapes[position], landscapes[other] = landscapes[other], landscapes[position]
+				break
+			}
+		}
+	}
+
+	var sb strings.Builder
+	numberCount, harborCount := 0, 0
+	for _, position := range positions {
+		if seaPositions[position] {
+			sb.WriteString(model.Sea.Code + model.NumberEmpty.Code + model.HarborNone.Code)
+			continue
+		}
+
+		landscapeCode := landscapes[position]
+		sb.WriteString(landscapeCode)
+		if landscapeCode == model.Desert.Code {
+			sb.WriteString(model.NumberEmpty.Code)
+		} else if number := locks[position].Number; number != "" {
+			sb.WriteString(number)
+		} else {
+			sb.WriteString(numberPool[numberCount])
+			numberCount++
+		}
+
+		if !harborPositions[position] {
+			sb.WriteString(model.HarborNone.Code)
+		} else if harbor := locks[position].Harbor; harbor != "" {
+			sb.WriteString(harbor)
+		} else {
+			sb.WriteString(harborPool[harborCount])
+			harborCount++
+		}
+	}
+
+	t := time.Now()
+	elapsed := t.Sub(start)
+	log.WithFields(log.Fields{
+		"Duration": elapsed,
+		"GameType": gameType.Name,
+		"Locks":    len(locks),
+	}).Debug(" < generateMapCode finish")
+
+	return sb.String()
+}
+
+// codePositions returns the positions of the tiles in the order of a game code, column by column from top to bottom
+func codePositions(gameType GameType) []string {
+	columns := make([]string, 0, len(gameType.BoardLayout))
+	for column := range gameType.BoardLayout {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	positions := make([]string, 0, gameType.TilesCount)
+	for _, column := range columns {
+		for row := 0; row < gameType.BoardLayout[column]; row++ {
+			positions = append(positions, fmt.Sprintf("%s%d", column, row))
+		}
+	}
+	return positions
+}
+
+// landscapeCodePool the codes of the land tiles of the game type, the deserts first and the others by their code
+func landscapeCodePool(gameType GameType) []string {
+	counts := []struct {
+		landscape *model.Landscape
+		count     int
+	}{
+		{model.Desert, gameType.DesertCount},
+		{model.Forest, gameType.ForestCount},
+		{model.Pasture, gameType.PastureCount},
+		{model.Field, gameType.FieldCount},
+		{model.Hill, gameType.RiverCount},
+		{model.Mountain, gameType.MountainCount},
+		{model.GoldField, gameType.GoldCount},
+	}
+	pool := make([]string, 0, gameType.TilesCount-gameType.SeaCount)
+	for _, landscapeCount := range counts {
+		for i := 0; i < landscapeCount.count; i++ {
+			pool = append(pool, landscapeCount.landscape.Code)
+		}
+	}
+	return pool
+}
+
+// numberCodePool the codes of the numbers of the game type, from the lowest to the highest number
+func numberCodePool(gameType GameType) []string {
+	pool := make([]string, 0, len(gameType.NumberSet))
+	for _, number := range gameType.NumberSet {
+		pool = append(pool, number.Code)
+	}
+	sort.Strings(pool)
+	return pool
+}
+
+// harborCodePool the codes of the harbors of the game type, the resource harbors by their code and the 3:1 harbors last
+func harborCodePool(gameType GameType) []string {
+	pool := make([]string, 0, len(gameType.HarborSet))
+	for _, harbor := range gameType.HarborSet {
+		pool = append(pool, harbor.Code)
+	}
+	sort.Slice(pool, func(i, j int) bool {
+		if (pool[i] == model.HarborAll.Code) != (pool[j] == model.HarborAll.Code) {
+			return pool[j] == model.HarborAll.Code
+		}
+		return pool[i] < pool[j]
+	})
+	return pool
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGameCode(t *testing.T) {
+	for _, key := range DefinedGames.Keys() {
+		definedGame := DefinedGames[key]
+		gameType := definedGame.GameType
+		code := GenerateGameCode(gameType, rand.New(rand.NewSource(1)))
+		assert.Equal(t, gameType.TilesCount*3, len(code), key)
+
+		board, err := definedGame.Inflate(code)
+		if !assert.NoError(t, err, key) {
+			continue
+		}
+		assert.Equal(t, gameType.Name, board.GameType.Name, key)
+		assert.True(t, matchesTileCounts(&board, &gameType), key)
+		assert.True(t, matchesSeaLayout(&board, gameType.SeaLayout), key)
+
+		numbers, harbors := 0, 0
+		for _, tiles := range board.Board {
+			for _, tile := range tiles {
+				if tile.Number.Code != model.NumberEmpty.Code {
+					numbers++
+				}
+				if tile.Harbor.Code != model.HarborNone.Code {
+					harbors++
+				}
+			}
+		}
+		assert.Equal(t, len(gameType.NumberSet), numbers, key)
+		assert.Equal(t, gameType.HarborCount, harbors, key)
+	}
+}
+
+func TestGenerateGameCodeIsDeterministic(t *testing.T) {
+	for _, key := range DefinedGames.Keys() {
+		definedGame := DefinedGames[key]
+		codeA := GenerateGameCode(definedGame.GameType, rand.New(rand.NewSource(7)))
+		codeB := GenerateGameCode(definedGame.GameType, rand.New(rand.NewSource(7)))
+		assert.Equal(t, codeA, codeB, key)
+		assert.NotEqual(t, codeA, GenerateGameCode(definedGame.GameType, rand.New(rand.NewSource(8))), key)
+
+		boardA, err := definedGame.Inflate(codeA)
+		if !assert.NoError(t, err, key) {
+			continue
+		}
+		boardB, err := definedGame.Inflate(codeB)
+		if !assert.NoError(t, err, key) {
+			continue
+		}
+		assert.Equal(t, boardA.GetGameCode(false), boardB.GetGameCode(false), key)
+	}
+}
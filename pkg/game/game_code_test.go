@@ -0,0 +1,126 @@
+package game
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	normalGameCode    = "2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0"
+	normalGameCodeV2  = "N2-6KHGC85E0PCY22ACH69P9Y2656HY8P1DM0CS685WJB"
+	largeGameCode     = "1d45b01d21f65b65b64h63j63f63c62i64f26z03i62i62e63g61g64c06z65g62d61h12c61a62e64a04e55j33h0"
+	seafarersGameCode = "2h03g63d47z68j66z67z67z61i17z67z67z64f25e67z67z62e61h67z67z67z67z65f54b67z67z68d61a04c65g37z67z62b67z67z67z63c0"
+)
+
+func TestGetGameCodeV2(t *testing.T) {
+	board, err := InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	code, err := board.GetGameCodeV2()
+	assert.NoError(t, err)
+	assert.Equal(t, normalGameCodeV2, code)
+	assert.Equal(t, CodeFormatV2, GameCodeFormat(code))
+	assert.Equal(t, CodeFormatV1, GameCodeFormat(normalGameCode))
+}
+
+func TestConvertGameCode(t *testing.T) {
+	codes := map[string]string{
+		normalGameCode:    "N2-",
+		largeGameCode:     "L2-",
+		seafarersGameCode: "SFI2-",
+	}
+	for code, prefix := range codes {
+		v2, err := ConvertGameCode(code, CodeFormatV2)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(v2, prefix), v2)
+
+		v1, err := ConvertGameCode(v2, CodeFormatV1)
+		assert.NoError(t, err)
+		assert.Equal(t, code, v1)
+
+		original, err := InflateGameFromCode(code)
+		assert.NoError(t, err)
+		inflated, err := InflateGameFromCode(v2)
+		assert.NoError(t, err)
+		assert.Equal(t, original.GameType.Name, inflated.GameType.Name)
+	}
+}
+
+func TestConvertGeneratedGameCodes(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		code := GenerateGameCodeNormalGame(random)
+		v2, err := ConvertGameCode(code, CodeFormatV2)
+		assert.NoError(t, err)
+		v1, err := ConvertGameCode(v2, CodeFormatV1)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.ReplaceAll(code, "_", ""), v1)
+	}
+}
+
+func TestInflateGameFromCodeV2IsForgiving(t *testing.T) {
+	board, err := InflateGameFromCode(strings.ToLower(normalGameCodeV2))
+	assert.NoError(t, err)
+	assert.Equal(t, normalGameCode, board.GetGameCode(false))
+
+	// the O of the body is read as a zero
+	board, err = InflateGameFromCode(strings.Replace(normalGameCodeV2, "0", "O", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, normalGameCode, board.GetGameCode(false))
+}
+
+func TestInflateGameFromCodeV2DetectsTypos(t *testing.T) {
+	body := strings.TrimPrefix(normalGameCodeV2, "N2-")
+	for i := range body {
+		for _, replacement := range base32Alphabet {
+			if byte(replacement) == body[i] {
+				continue
+			}
+			typo := "N2-" + body[:i] + string(replacement) + body[i+1:]
+			_, err := InflateGameFromCode(typo)
+			assert.ErrorIs(t, err, ErrInvalidChecksum, typo)
+		}
+	}
+
+	// swapping two neighbouring tiles
+	swapped := "N2-" + body[2:4] + body[0:2] + body[4:]
+	_, err := InflateGameFromCode(swapped)
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+}
+
+func TestInflateGameFromCodeV2Invalid(t *testing.T) {
+	_, err := InflateGameFromCode("X2-" + strings.TrimPrefix(normalGameCodeV2, "N2-"))
+	assert.ErrorIs(t, err, ErrUnrecognizableCode)
+
+	_, err = InflateGameFromCode("N3-" + strings.TrimPrefix(normalGameCodeV2, "N2-"))
+	assert.ErrorIs(t, err, ErrUnrecognizableCode)
+
+	_, err = InflateGameFromCode(normalGameCodeV2[:len(normalGameCodeV2)-2])
+	assert.Error(t, err)
+
+	_, err = ConvertGameCode(normalGameCode, CodeFormat("v3"))
+	assert.Error(t, err)
+}
+
+func TestParseCodeFormat(t *testing.T) {
+	format, ok := ParseCodeFormat("V2")
+	assert.True(t, ok)
+	assert.Equal(t, CodeFormatV2, format)
+	_, ok = ParseCodeFormat("v3")
+	assert.False(t, ok)
+}
+
+func TestDefinitionCodeTag(t *testing.T) {
+	codeTag, err := definitionCodeTag(GameDefinition{Key: "triangle-json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "TRIANGLEJSON", codeTag)
+
+	codeTag, err = definitionCodeTag(GameDefinition{Key: "triangle", CodeTag: "tri"})
+	assert.NoError(t, err)
+	assert.Equal(t, "TRI", codeTag)
+
+	_, err = definitionCodeTag(GameDefinition{Key: "triangle", CodeTag: "T3"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,309 @@
+package game
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	consoleNormal  = "normal"
+	consoleLarge   = "large"
+	consoleColumns = "columns"
+)
+
+//go:embed definitions/*.yaml
+var bundledDefinitions embed.FS
+
+// GameDefinition the declarative description of a game type, as read from a YAML or JSON definition file
+// Positions are written as column and row, such as c0 for the first tile of the third column
+// The CodeTag identifies the game type in v2 game codes, it defaults to the letters of the key in capitals
+// The tiles that meet in an intersection are derived from the layout, neighbouring columns differ by one tile
+type GameDefinition struct {
+	Key         string         `json:"key" yaml:"key"`
+	Name        string         `json:"name" yaml:"name"`
+	CodeTag     string         `json:"codeTag" yaml:"codeTag"`
+	Console     string         `json:"console" yaml:"console"`
+	Layout      map[string]int `json:"layout" yaml:"layout"`
+	Tiles       map[string]int `json:"tiles" yaml:"tiles"`
+	Numbers     []int          `json:"numbers" yaml:"numbers"`
+	Harbors     []string       `json:"harbors" yaml:"harbors"`
+	HarborSlots []string       `json:"harborSlots" yaml:"harborSlots"`
+	SeaSlots    []string       `json:"seaSlots" yaml:"seaSlots"`
+	Rules       GameRules      `json:"rules" yaml:"rules"`
+}
+
+// LoadGameDefinitions loads all game definition files (.yaml, .yml or .json) from a directory, and registers them in the DefinedGames
+// Rules that are not in a definition file fall back to the default rules of the normal game
+func LoadGameDefinitions(directory string) error {
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return fmt.Errorf("cannot read game definitions directory %s: %w", directory, err)
+	}
+
+	for _, file := range files {
+		extension := strings.ToLower(filepath.Ext(file.Name()))
+		if file.IsDir() || (extension != ".yaml" && extension != ".yml" && extension != ".json") {
+			continue
+		}
+
+		path := filepath.Join(directory, file.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot read game definition %s: %w", path, err)
+		}
+
+		definition := GameDefinition{Rules: DefaultGameRulesNormal}
+		if err := parseGameDefinition(content, extension == ".json", &definition); err != nil {
+			return fmt.Errorf("invalid game definition %s: %w", path, err)
+		}
+		gameType, err := CreateGameType(definition)
+		if err != nil {
+			return fmt.Errorf("invalid game definition %s: %w", path, err)
+		}
+		definition.Rules.GameTypeString = definition.Key
+		definedGame := DefinedGame{GameType: gameType, Rules: definition.Rules, Inflate: inflaterForDefinedGame(gameType)}
+		if err := DefinedGames.Register(definition.Key, definedGame); err != nil {
+			return fmt.Errorf("invalid game definition %s: %w", path, err)
+		}
+
+		log.WithFields(log.Fields{
+			"Key":  definition.Key,
+			"Name": gameType.Name,
+			"File": path,
+		}).Info("Loaded game definition")
+	}
+	return nil
+}
+
+// CreateGameType creates a game type from a definition, after verifying the definition is complete and consistent
+func CreateGameType(definition GameDefinition) (GameType, error) {
+	if definition.Key == "" || definition.Name == "" {
+		return GameType{}, fmt.Errorf("a definition requires a key and a name")
+	}
+	if len(definition.Layout) == 0 {
+		return GameType{}, fmt.Errorf("a definition requires a layout")
+	}
+
+	totalTiles := 0
+	for column, tilesInColumn := range definition.Layout {
+		if len(column) != 1 || column[0] < 'a' || column[0] > 'z' {
+			return GameType{}, fmt.Errorf("column %s is not a single letter from a to z", column)
+		}
+		if tilesInColumn < 1 {
+			return GameType{}, fmt.Errorf("column %s should have at least one tile, not %d", column, tilesInColumn)
+		}
+		totalTiles += tilesInColumn
+	}
+	hexes, err := HexPositions(definition.Layout)
+	if err != nil {
+		return GameType{}, err
+	}
+
+	codeTag, err := definitionCodeTag(definition)
+	if err != nil {
+		return GameType{}, err
+	}
+
+	gameType := GameType{
+		Name:        definition.Name,
+		CodeTag:     codeTag,
+		TilesCount:  totalTiles,
+		BoardLayout: definition.Layout,
+		Hexes:       hexes,
+	}
+
+	landTiles := 0
+	for landscape, count := range definition.Tiles {
+		switch landscape {
+		case "desert":
+			gameType.DesertCount = count
+		case "forest":
+			gameType.ForestCount = count
+		case "pasture":
+			gameType.PastureCount = count
+		case "field":
+			gameType.FieldCount = count
+		case "hill":
+			gameType.RiverCount = count
+		case "mountain":
+			gameType.MountainCount = count
+		case "gold":
+			gameType.GoldCount = count
+		default:
+			return GameType{}, fmt.Errorf("unknown tile %s, expected one of desert, forest, pasture, field, hill, mountain or gold", landscape)
+		}
+		landTiles += count
+	}
+
+	for _, position := range definition.SeaSlots {
+		if !definition.hasPosition(position) {
+			return GameType{}, fmt.Errorf("sea slot %s is not on the board", position)
+		}
+	}
+	gameType.SeaLayout = definition.SeaSlots
+	gameType.SeaCount = len(definition.SeaSlots)
+	if landTiles+gameType.SeaCount != totalTiles {
+		return GameType{}, fmt.Errorf("the layout has %d tiles, but there are %d land tiles and %d sea slots", totalTiles, landTiles, gameType.SeaCount)
+	}
+
+	if len(definition.Numbers) != landTiles-gameType.DesertCount {
+		return GameType{}, fmt.Errorf("expected %d numbers, one for every land tile except the desert, found %d", landTiles-gameType.DesertCount, len(definition.Numbers))
+	}
+	gameType.NumberSet = make([]*model.Number, 0, len(definition.Numbers))
+	for _, value := range definition.Numbers {
+		number := findNumber(value)
+		if number == nil {
+			return GameType{}, fmt.Errorf("%d is not a valid number", value)
+		}
+		gameType.NumberSet = append(gameType.NumberSet, number)
+	}
+
+	if len(definition.Harbors) != len(definition.HarborSlots) {
+		return GameType{}, fmt.Errorf("expected a harbor slot for each of the %d harbors, found %d", len(definition.Harbors), len(definition.HarborSlots))
+	}
+	gameType.HarborCount = len(definition.Harbors)
+	gameType.HarborSet = make([]*model.Harbor, 0, len(definition.Harbors))
+	for _, resource := range definition.Harbors {
+		harbor := findHarbor(resource)
+		if harbor == nil {
+			return GameType{}, fmt.Errorf("%s is not a valid harbor, expected a resource name or all", resource)
+		}
+		gameType.HarborSet = append(gameType.HarborSet, harbor)
+	}
+	for _, position := range definition.HarborSlots {
+		if !definition.hasPosition(position) || definition.isSea(position) {
+			return GameType{}, fmt.Errorf("harbor slot %s is not a land tile on the board", position)
+		}
+	}
+	gameType.HarborLayout = definition.HarborSlots
+
+	gameType.AdjacentTileGroups = computeAdjacentTileGroups(hexes, definition.SeaSlots)
+	gameType.NeighbouringTiles = computeNeighbouringTiles(hexes)
+
+	switch definition.Console {
+	case consoleNormal:
+		gameType.ToConsole = printNormalGameToConsole
+	case consoleLarge:
+		gameType.ToConsole = printLargeGameToConsole
+	case consoleColumns, "":
+		gameType.ToConsole = printColumnsToConsole
+	default:
+		return GameType{}, fmt.Errorf("unknown console output %s, expected one of normal, large or columns", definition.Console)
+	}
+
+	return gameType, nil
+}
+
+// createBundledGameType creates one of the game types that are bundled with the application
+// The bundled definitions are part of the binary, so an invalid definition is a programming error
+func createBundledGameType(key string) GameType {
+	content, err := bundledDefinitions.ReadFile("definitions/" + key + ".yaml")
+	if err != nil {
+		panic(fmt.Sprintf("bundled game definition %s not found: %v", key, err))
+	}
+	var definition GameDefinition
+	if err := parseGameDefinition(content, false, &definition); err != nil {
+		panic(fmt.Sprintf("bundled game definition %s is invalid: %v", key, err))
+	}
+	gameType, err := CreateGameType(definition)
+	if err != nil {
+		panic(fmt.Sprintf("bundled game definition %s is invalid: %v", key, err))
+	}
+	return gameType
+}
+
+// definitionCodeTag returns the code tag of the definition, which consists of capitals only
+func definitionCodeTag(definition GameDefinition) (string, error) {
+	codeTag := definition.CodeTag
+	if codeTag == "" {
+		codeTag = strings.Map(func(character rune) rune {
+			if character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z' {
+				return character
+			}
+			return -1
+		}, definition.Key)
+	}
+	codeTag = strings.ToUpper(codeTag)
+	if codeTag == "" {
+		return "", fmt.Errorf("a definition requires a code tag when its key has no letters")
+	}
+	for _, character := range codeTag {
+		if character < 'A' || character > 'Z' {
+			return "", fmt.Errorf("code tag %s should consist of letters only", definition.CodeTag)
+		}
+	}
+	return codeTag, nil
+}
+
+func parseGameDefinition(content []byte, isJSON bool, definition *GameDefinition) error {
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(definition)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	return decoder.Decode(definition)
+}
+
+func (definition GameDefinition) hasPosition(position string) bool {
+	column, row, ok := parsePosition(position)
+	if !ok {
+		return false
+	}
+	return row < definition.Layout[column]
+}
+
+func (definition GameDefinition) isSea(position string) bool {
+	for _, seaSlot := range definition.SeaSlots {
+		if seaSlot == position {
+			return true
+		}
+	}
+	return false
+}
+
+func findNumber(value int) *model.Number {
+	for _, number := range model.Numbers {
+		if number.Number == value && number.Number != model.NumberEmpty.Number {
+			found := number
+			return &found
+		}
+	}
+	return nil
+}
+
+func findHarbor(resource string) *model.Harbor {
+	for _, harbor := range model.Harbors {
+		if harbor != *model.HarborNone && strings.EqualFold(harbor.Resource.Name, resource) {
+			found := harbor
+			return &found
+		}
+	}
+	return nil
+}
+
+func matchesTileCounts(board *Board, gameType *GameType) bool {
+	landscapes := make(map[string]int)
+	for _, tiles := range board.Board {
+		for _, tile := range tiles {
+			landscapes[tile.Landscape.Code]++
+		}
+	}
+	return landscapes[model.Desert.Code] == gameType.DesertCount &&
+		landscapes[model.Forest.Code] == gameType.ForestCount &&
+		landscapes[model.Pasture.Code] == gameType.PastureCount &&
+		landscapes[model.Field.Code] == gameType.FieldCount &&
+		landscapes[model.Hill.Code] == gameType.RiverCount &&
+		landscapes[model.Mountain.Code] == gameType.MountainCount &&
+		landscapes[model.GoldField.Code] == gameType.GoldCount
+}
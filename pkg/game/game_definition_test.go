@@ -0,0 +1,125 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const triangleDefinition = `
+key: triangle
+name: Triangle
+layout:
+  a: 1
+  b: 2
+tiles:
+  desert: 1
+  forest: 1
+  hill: 1
+numbers: [6, 8]
+harbors: [all]
+harborSlots: [a0]
+rules:
+  maxOver300: 1
+`
+
+func TestBundledNormalGame(t *testing.T) {
+	assert.Equal(t, 19, NormalGame.TilesCount)
+	assert.Equal(t, 18, len(NormalGame.NumberSet))
+	assert.Equal(t, 9, len(NormalGame.HarborSet))
+	assert.Equal(t, 9, len(NormalGame.HarborLayout))
+	assert.Equal(t, 24, len(NormalGame.AdjacentTileGroups))
+}
+
+func TestBundledLargeGame(t *testing.T) {
+	assert.Equal(t, 30, LargeGame.TilesCount)
+	assert.Equal(t, 2, LargeGame.DesertCount)
+	assert.Equal(t, 28, len(LargeGame.NumberSet))
+	assert.Equal(t, 11, len(LargeGame.HarborSet))
+	assert.Equal(t, 42, len(LargeGame.AdjacentTileGroups))
+}
+
+func TestLoadGameDefinitions(t *testing.T) {
+	directory := t.TempDir()
+	jsonDefinition := `{"key": "triangle-json", "name": "Triangle JSON", "layout": {"a": 1, "b": 2},
+		"tiles": {"desert": 1, "field": 2}, "numbers": [5, 9], "harbors": ["grain"], "harborSlots": ["b1"]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "triangle.yaml"), []byte(triangleDefinition), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "triangle.json"), []byte(jsonDefinition), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "README.md"), []byte("not a definition"), 0o600))
+	t.Cleanup(func() {
+		delete(DefinedGames, "triangle")
+		delete(DefinedGames, "triangle-json")
+	})
+
+	err := LoadGameDefinitions(directory)
+	assert.NoError(t, err)
+
+	triangle, ok := DefinedGames["triangle"]
+	assert.True(t, ok)
+	assert.Equal(t, "Triangle", triangle.GameType.Name)
+	assert.Equal(t, 3, triangle.GameType.TilesCount)
+	assert.Equal(t, [][]string{{"a0", "b0", "b1"}}, triangle.GameType.AdjacentTileGroups)
+	assert.Equal(t, 1, triangle.Rules.MaxOver300)
+	assert.Equal(t, DefaultGameRulesNormal.MinimumResourceScore, triangle.Rules.MinimumResourceScore)
+	assert.Equal(t, "triangle", triangle.Rules.GameTypeString)
+
+	triangleJSON, ok := DefinedGames["triangle-json"]
+	assert.True(t, ok)
+	assert.Equal(t, 2, triangleJSON.GameType.FieldCount)
+	assert.Equal(t, "b1", triangleJSON.GameType.HarborLayout[0])
+
+	board, err := InflateDefinedGameFromCode("6z63d63g3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Triangle JSON", board.GameType.Name)
+}
+
+func TestLoadGameDefinitionsDuplicateKey(t *testing.T) {
+	directory := t.TempDir()
+	definition := "key: normal\nname: Normal\nlayout: {a: 1}\ntiles: {desert: 1}\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "normal.yml"), []byte(definition), 0o600))
+
+	err := LoadGameDefinitions(directory)
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestLoadGameDefinitionsDuplicateCodeTag(t *testing.T) {
+	directory := t.TempDir()
+	definition := "key: small\nname: Small\ncodeTag: n\nlayout: {a: 1}\ntiles: {desert: 1}\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "small.yml"), []byte(definition), 0o600))
+
+	err := LoadGameDefinitions(directory)
+	assert.ErrorContains(t, err, "code tag N is already used by game type normal")
+	_, exists := DefinedGames["small"]
+	assert.False(t, exists)
+}
+
+func TestCreateGameTypeInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(definition *GameDefinition)
+		expected string
+	}{
+		{"missing key", func(d *GameDefinition) { d.Key = "" }, "requires a key"},
+		{"invalid column", func(d *GameDefinition) { d.Layout["ab"] = 1 }, "not a single letter"},
+		{"tile count", func(d *GameDefinition) { d.Tiles["forest"] = 2 }, "the layout has 3 tiles"},
+		{"unknown tile", func(d *GameDefinition) { d.Tiles["swamp"] = 0 }, "unknown tile swamp"},
+		{"number count", func(d *GameDefinition) { d.Numbers = []int{6} }, "expected 2 numbers"},
+		{"invalid number", func(d *GameDefinition) { d.Numbers = []int{6, 7} }, "7 is not a valid number"},
+		{"unknown harbor", func(d *GameDefinition) { d.Harbors = []string{"gold"} }, "gold is not a valid harbor"},
+		{"harbor slot", func(d *GameDefinition) { d.HarborSlots = []string{"c0"} }, "harbor slot c0"},
+		{"hex grid", func(d *GameDefinition) { d.Layout["b"] = 1; d.Tiles["hill"] = 0 }, "does not fit on a hex grid"},
+		{"console", func(d *GameDefinition) { d.Console = "fancy" }, "unknown console output"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var definition GameDefinition
+			assert.NoError(t, parseGameDefinition([]byte(triangleDefinition), false, &definition))
+			test.change(&definition)
+			_, err := CreateGameType(definition)
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
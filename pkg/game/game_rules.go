@@ -1,19 +1,28 @@
 package game
 
 // GameRules the rules for generating this Game's map
+// The tags are used for the rules section of game definition files
+// With VariableHarbors the harbors are placed on any coastal edge, instead of on the HarborLayout of the game type
 type GameRules struct {
-	MaximumScore              int
-	MinimumScore              int
-	MaximumResourceScore      int
-	MinimumResourceScore      int
-	MaxOver300                int
-	MaxSameLandscapePerRow    int
-	MaxSameLandscapePerColumn int
-	AdjacentSame              int
-	GameType                  int
-	GameTypeString            string
-	Generations               int
-	Delimiter                 string
+	MaximumScore              int    `json:"maximumScore" yaml:"maximumScore"`
+	MinimumScore              int    `json:"minimumScore" yaml:"minimumScore"`
+	MaximumResourceScore      int    `json:"maximumResourceScore" yaml:"maximumResourceScore"`
+	MinimumResourceScore      int    `json:"minimumResourceScore" yaml:"minimumResourceScore"`
+	MaxOver300                int    `json:"maxOver300" yaml:"maxOver300"`
+	MaxSameLandscapePerRow    int    `json:"maxSameLandscapePerRow" yaml:"maxSameLandscapePerRow"`
+	MaxSameLandscapePerColumn int    `json:"maxSameLandscapePerColumn" yaml:"maxSameLandscapePerColumn"`
+	AdjacentSame              int    `json:"adjacentSame" yaml:"adjacentSame"`
+	NoAdjacentRed             bool   `json:"noAdjacentRed" yaml:"noAdjacentRed"`
+	NoAdjacentSameNumber      bool   `json:"noAdjacentSameNumber" yaml:"noAdjacentSameNumber"`
+	NoSameNumberPerResource   bool   `json:"noSameNumberPerResource" yaml:"noSameNumberPerResource"`
+	MaxFirstSeatAdvantage     int    `json:"maxFirstSeatAdvantage" yaml:"maxFirstSeatAdvantage"`
+	MaxSeatGap                int    `json:"maxSeatGap" yaml:"maxSeatGap"`
+	Players                   int    `json:"players" yaml:"players"`
+	GameType                  int    `json:"gameType" yaml:"gameType"`
+	GameTypeString            string `json:"gameTypeString" yaml:"gameTypeString"`
+	Generations               int    `json:"generations" yaml:"generations"`
+	Delimiter                 string `json:"delimiter" yaml:"delimiter"`
+	VariableHarbors           bool   `json:"variableHarbors" yaml:"variableHarbors"`
 }
 
 var (
@@ -26,24 +35,53 @@ var (
 		MaxSameLandscapePerRow:    2,
 		MaxSameLandscapePerColumn: 2,
 		AdjacentSame:              0,
+		NoAdjacentRed:             false,
+		NoAdjacentSameNumber:      false,
+		NoSameNumberPerResource:   false,
 		GameType:                  0,
+		Players:                   4,
 		GameTypeString:            "Normal",
 		Generations:               2500,
 		Delimiter:                 "_",
 	}
 
+	// DefaultGameRulesLarge has a lower MinimumScore than the Normal game, as with its 42 groups of adjacent tiles
+	// a higher minimum leaves too few valid boards within the Generations
 	DefaultGameRulesLarge = GameRules{
 		MaximumScore:              365,
-		MinimumScore:              156,
+		MinimumScore:              138,
 		MaximumResourceScore:      130,
 		MinimumResourceScore:      65,
 		MaxOver300:                22,
 		MaxSameLandscapePerRow:    3,
 		MaxSameLandscapePerColumn: 3,
 		AdjacentSame:              0,
+		NoAdjacentRed:             false,
+		NoAdjacentSameNumber:      false,
+		NoSameNumberPerResource:   false,
 		GameType:                  1,
+		Players:                   6,
 		GameTypeString:            "Large",
 		Generations:               5000,
 		Delimiter:                 "_",
 	}
+
+	DefaultGameRulesSeafarers = GameRules{
+		MaximumScore:              361,
+		MinimumScore:              165,
+		MaximumResourceScore:      130,
+		MinimumResourceScore:      30,
+		MaxOver300:                6,
+		MaxSameLandscapePerRow:    2,
+		MaxSameLandscapePerColumn: 2,
+		AdjacentSame:              0,
+		NoAdjacentRed:             false,
+		NoAdjacentSameNumber:      false,
+		NoSameNumberPerResource:   false,
+		GameType:                  2,
+		Players:                   4,
+		GameTypeString:            "Seafarers",
+		Generations:               2500,
+		Delimiter:                 "_",
+	}
 )
@@ -10,9 +10,15 @@ var LargeGame = CreateLargeGame()
 type PrintBoardToConsole func(b *Board)
 
 // GameType the information for the type of game
-// Should be exhaustive and will be expanded for supporting alternative game types such as Seafarers
+// Should be exhaustive for supporting alternative game types such as Seafarers
+// Tiles on the positions in the SeaLayout are always Sea, all other tiles are shuffled
+// CodeTag identifies the game type in v2 game codes, such as N for the Normal game
+// Hexes maps every position, such as c2, onto its hex coordinates
+// AdjacentTileGroups lists the positions of every three land tiles that meet in an intersection
+// NeighbouringTiles lists the positions of every two tiles that share an edge
 type GameType struct {
 	Name               string
+	CodeTag            string
 	TilesCount         int
 	DesertCount        int
 	ForestCount        int
@@ -20,11 +26,16 @@ type GameType struct {
 	FieldCount         int
 	RiverCount         int
 	MountainCount      int
+	GoldCount          int
+	SeaCount           int
 	HarborCount        int
 	AdjacentTileGroups [][]string
+	NeighbouringTiles  [][2]string
 	NumberSet          []*model.Number
 	HarborSet          []*model.Harbor
 	HarborLayout       []string
+	SeaLayout          []string
 	BoardLayout        map[string]int
+	Hexes              map[string]model.Hex
 	ToConsole          PrintBoardToConsole
 }
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// HexPositions maps the positions of a board layout, such as c2, onto hex coordinates
+// The columns are placed from left to right, each column centered on the middle of the board,
+// as in the console output, so two neighbouring columns have to differ by one tile
+func HexPositions(boardLayout map[string]int) (map[string]model.Hex, error) {
+	columns := make([]string, 0, len(boardLayout))
+	for column := range boardLayout {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	positions := make(map[string]model.Hex)
+	for q, column := range columns {
+		// a column with one tile less starts half a tile lower, and moving one column to the right
+		// is already half a tile down, so the top of the column is at R = (first - tiles - q) / 2
+		offset := boardLayout[columns[0]] - boardLayout[column] - q
+		if offset%2 != 0 {
+			return nil, fmt.Errorf("column %s with %d tiles does not fit on a hex grid next to column %s", column, boardLayout[column], columns[q-1])
+		}
+		for row := 0; row < boardLayout[column]; row++ {
+			positions[fmt.Sprintf("%s%d", column, row)] = model.Hex{Q: q, R: offset/2 + row}
+		}
+	}
+	return positions, nil
+}
+
+// computeAdjacentTileGroups returns every group of three tiles that meet in a single intersection
+// Groups with a tile that is excluded, such as a Sea tile, are left out
+func computeAdjacentTileGroups(positions map[string]model.Hex, excluded []string) [][]string {
+	tiles := make(map[model.Hex]string)
+	for position, hex := range positions {
+		tiles[hex] = position
+	}
+	for _, position := range excluded {
+		delete(tiles, positions[position])
+	}
+
+	tileGroups := make([][]string, 0)
+	seen := make(map[model.HexVertex]bool)
+	for _, hex := range sortedHexes(tiles) {
+		for _, vertex := range hex.Vertices() {
+			if seen[vertex] {
+				continue
+			}
+			seen[vertex] = true
+
+			tileGroup := make([]string, 0, len(vertex))
+			for _, vertexHex := range vertex {
+				if position, ok := tiles[vertexHex]; ok {
+					tileGroup = append(tileGroup, position)
+				}
+			}
+			if len(tileGroup) == len(vertex) {
+				tileGroups = append(tileGroups, tileGroup)
+			}
+		}
+	}
+	return tileGroups
+}
+
+// computeNeighbouringTiles returns every pair of tiles that share an edge, ordered by their positions
+func computeNeighbouringTiles(positions map[string]model.Hex) [][2]string {
+	tiles := make(map[model.Hex]string)
+	for position, hex := range positions {
+		tiles[hex] = position
+	}
+
+	pairs := make([][2]string, 0)
+	for _, hex := range sortedHexes(tiles) {
+		for _, neighbour := range hex.Neighbours() {
+			if position, ok := tiles[neighbour]; ok && hex.Less(neighbour) {
+				pairs = append(pairs, [2]string{tiles[hex], position})
+			}
+		}
+	}
+	return pairs
+}
+
+func sortedHexes(tiles map[model.Hex]string) []model.Hex {
+	hexes := make([]model.Hex, 0, len(tiles))
+	for hex := range tiles {
+		hexes = append(hexes, hex)
+	}
+	sort.Slice(hexes, func(i, j int) bool {
+		return hexes[i].Less(hexes[j])
+	})
+	return hexes
+}
+
+// parsePosition splits a position such as c2 or a10 in its column and row
+func parsePosition(position string) (string, int, bool) {
+	if len(position) < 2 {
+		return "", 0, false
+	}
+	row, err := strconv.Atoi(position[1:])
+	if err != nil || row < 0 {
+		return "", 0, false
+	}
+	return position[0:1], row, true
+}
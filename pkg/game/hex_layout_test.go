@@ -0,0 +1,67 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexPositionsNormal(t *testing.T) {
+	positions, err := HexPositions(NormalGame.BoardLayout)
+	assert.NoError(t, err)
+	assert.Equal(t, 19, len(positions))
+
+	// the center of the board is surrounded by the six tiles around it
+	center := positions["c2"]
+	for _, position := range []string{"b1", "b2", "c1", "c3", "d1", "d2"} {
+		assert.True(t, center.IsNeighbour(positions[position]), position)
+	}
+	assert.False(t, center.IsNeighbour(positions["a0"]))
+	assert.Equal(t, 2, center.Distance(positions["a0"]))
+}
+
+func TestHexPositionsRowsBeyondNine(t *testing.T) {
+	layout := map[string]int{"a": 11, "b": 12, "c": 11}
+	positions, err := HexPositions(layout)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Hex{Q: 1, R: 10}, positions["b11"])
+	assert.True(t, positions["a10"].IsNeighbour(positions["b11"]))
+	assert.True(t, positions["c10"].IsNeighbour(positions["b11"]))
+
+	groups := computeAdjacentTileGroups(positions, nil)
+	assert.Contains(t, groups, []string{"a10", "b10", "b11"})
+	assert.Contains(t, groups, []string{"b10", "b11", "c10"})
+}
+
+func TestAdjacentTileGroupsNormal(t *testing.T) {
+	groups := NormalGame.AdjacentTileGroups
+	assert.Equal(t, 24, len(groups))
+	assert.Contains(t, groups, []string{"a1", "a2", "b2"})
+	assert.NotContains(t, groups, []string{"a1", "a2", "b1"})
+
+	seen := make(map[model.HexVertex]bool)
+	for _, group := range groups {
+		vertex := model.NewHexVertex(NormalGame.Hexes[group[0]], NormalGame.Hexes[group[1]], NormalGame.Hexes[group[2]])
+		assert.False(t, seen[vertex], "duplicate group %v", group)
+		seen[vertex] = true
+	}
+}
+
+func TestAdjacentTileGroupsExcludeSea(t *testing.T) {
+	gameType := SeafarersGames[SeafarersGameTypePrefix+"new-shores"]
+	assert.Equal(t, 12, len(gameType.AdjacentTileGroups))
+	for _, group := range gameType.AdjacentTileGroups {
+		for _, position := range group {
+			assert.NotContains(t, gameType.SeaLayout, position)
+		}
+	}
+}
+
+func TestElementRowsBeyondNine(t *testing.T) {
+	board := Board{Board: map[string][]*model.Tile{"a": make([]*model.Tile, 11)}}
+	board.Board["a"][10] = &model.Tile{Number: *model.Number8}
+	assert.Equal(t, ".8", board.element("10an"))
+	assert.Equal(t, "139", board.element("10aw"))
+	assert.Equal(t, *model.Number8, board.tile("a10").Number)
+}
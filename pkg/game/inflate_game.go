@@ -6,13 +6,25 @@ import (
 	"github.com/kennygrant/sanitize"
 	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/joostvdg/cmg/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
 
+// ErrUnrecognizableCode the game code does not belong to any of the game types
+var ErrUnrecognizableCode = errors.New("unrecognizable game code")
+
+// InflateGameFromCode inflates a board from the game code of any game type, in either format
+// The game type of a v2 code is in its prefix, a v1 code is inflated by the first game type of the DefinedGames that recognizes it
+// Returns ErrUnrecognizableCode when no game type recognizes the v1 code, or has the code tag of the v2 code
+func InflateGameFromCode(code string) (Board, error) {
+	if GameCodeFormat(code) == CodeFormatV2 {
+		return InflateGameFromCodeV2(code)
+	}
+	return DefinedGames.Inflate(code)
+}
+
 func inflateGameFromCode(code string, gameLayout map[string]int, gameType *GameType) (Board, error) {
 	start := time.Now()
 	log.Debug(" > Inflate Game from Game Code start")
@@ -78,12 +90,10 @@ func inflateGameFromCode(code string, gameLayout map[string]int, gameType *GameT
 		boardMap[column] = tiles
 	}
 
-	var group sync.WaitGroup
 	board := Board{
-		Board:     boardMap,
-		GameType:  *gameType,
-		WaitGroup: group,
-		Tiles:     allTiles,
+		Board:    boardMap,
+		GameType: *gameType,
+		Tiles:    allTiles,
 	}
 	t := time.Now()
 	elapsed := t.Sub(start)
@@ -2,152 +2,16 @@ package game
 
 import (
 	"fmt"
-	"github.com/joostvdg/cmg/pkg/model"
 )
 
 func InflateLargeGameFromCode(code string, gameType *GameType) (Board, error) {
-	gameLayout := generateLargeGameLayout()
-	return inflateGameFromCode(code, gameLayout, gameType)
+	return inflateGameFromCode(code, LargeGame.BoardLayout, gameType)
 }
 
-func generateLargeGameLayout() map[string]int {
-	var boardLayout map[string]int
-	boardLayout = make(map[string]int)
-	boardLayout["a"] = 3
-	boardLayout["b"] = 4
-	boardLayout["c"] = 5
-	boardLayout["d"] = 6
-	boardLayout["e"] = 5
-	boardLayout["f"] = 4
-	boardLayout["g"] = 3
-
-	return boardLayout
-}
-
-func generateNumberSetLarge(numberOfTiles int) []*model.Number {
-	numbers := make([]*model.Number, 0, numberOfTiles-2) // two desert tiles
-	numbers = append(numbers, model.Number2)
-	numbers = append(numbers, model.Number2)
-	numbers = append(numbers, model.Number3)
-	numbers = append(numbers, model.Number3)
-	numbers = append(numbers, model.Number3)
-	numbers = append(numbers, model.Number4)
-	numbers = append(numbers, model.Number4)
-	numbers = append(numbers, model.Number4)
-	numbers = append(numbers, model.Number5)
-	numbers = append(numbers, model.Number5)
-	numbers = append(numbers, model.Number5)
-	numbers = append(numbers, model.Number6)
-	numbers = append(numbers, model.Number6)
-	numbers = append(numbers, model.Number6)
-	numbers = append(numbers, model.Number8)
-	numbers = append(numbers, model.Number8)
-	numbers = append(numbers, model.Number8)
-	numbers = append(numbers, model.Number9)
-	numbers = append(numbers, model.Number9)
-	numbers = append(numbers, model.Number9)
-	numbers = append(numbers, model.Number10)
-	numbers = append(numbers, model.Number10)
-	numbers = append(numbers, model.Number10)
-	numbers = append(numbers, model.Number11)
-	numbers = append(numbers, model.Number11)
-	numbers = append(numbers, model.Number11)
-	numbers = append(numbers, model.Number12)
-	numbers = append(numbers, model.Number12)
-	return numbers
-}
-
-func generateHarborSetLarge(numberOfHarbors int) []*model.Harbor {
-	harbors := make([]*model.Harbor, 0, numberOfHarbors)
-	harbors = append(harbors, model.HarborGrain)
-	harbors = append(harbors, model.HarborBrick)
-	harbors = append(harbors, model.HarborOre)
-	harbors = append(harbors, model.HarborWool)
-	harbors = append(harbors, model.HarborWool)
-	harbors = append(harbors, model.HarborLumber)
-	harbors = append(harbors, model.HarborAll)
-	harbors = append(harbors, model.HarborAll)
-	harbors = append(harbors, model.HarborAll)
-	harbors = append(harbors, model.HarborAll)
-	harbors = append(harbors, model.HarborAll)
-
-	return harbors
-}
-
-func generateHarborLayoutLarge() []string {
-	return []string{"d0", "a0", "a1", "a2", "c4", "e4", "f3", "g2", "g1", "g0", "e0"}
-}
-
-// CreateLargeGame creates a Large game for up to four players.
-// Will create a board layout as shown below.
+// CreateLargeGame creates a Large game for five or six players.
+// The layout of the board is shown at printLargeGameToConsole.
 func CreateLargeGame() GameType {
-	game := GameType{
-		Name:          "Large",
-		TilesCount:    30,
-		DesertCount:   2,
-		ForestCount:   6,
-		PastureCount:  6,
-		FieldCount:    6,
-		RiverCount:    5,
-		MountainCount: 5,
-		HarborCount:   11,
-		NumberSet:     generateNumberSetLarge(30),
-		HarborSet:     generateHarborSetLarge(11),
-		BoardLayout:   generateLargeGameLayout(),
-		HarborLayout:  generateHarborLayoutLarge(),
-		ToConsole:     printLargeGameToConsole,
-	}
-	// a0 - a1 - b0, a0 - b0 - b1
-	// a1 - a2 - b1, a1 - b1 - b2
-	// a2 - b2 - b3
-	// b0 - c0 - c1, b0 - b1 - c1
-	// b1 - c1 - c2, b1 - b2 - c2
-	// b2 - c2 - c3, b2 - b3 - c3
-	// b3 - c3 - c4
-	// c0 - d0 - d1, c0 - c1 - d1
-	// c1 - d1 - d2, c1 - c2 - d2
-	// c2 - d2 - d3, c2 - c3 - d3
-	// c3 - d3 - d4, c3 - c4 - d4
-	// c4 - d4 - d5
-	// d0 - d1 - e0
-	// d1 - e0 - e1, d1 - d2 - e1
-	// d2 - e1 - e2, d2 - d3 - e2
-	// d3 - e2 - e3, d3 - d4 - e3
-	// d4 - e3 - e4, d4 - d5 - e4
-	// e0 - e1 - f0
-	// e1 - f0 - f1, e1 - e2 - f1
-	// e2 - f1 - f2, e2 - e3 - f2
-	// e3 - f2 - f3, e3 - e4 - f3
-	// f0 - f1 - g0
-	// f1 - g0 - g1, f1 - f2 - g1
-	// f2 - g1 - g2, f2 - f3 - g2
-	game.AdjacentTileGroups = [][]string{
-		{"0aw", "1aw", "1bw"}, {"0aw", "0bw", "1bw"},
-		{"1aw", "2aw", "1bw"}, {"1aw", "1bw", "2bw"},
-		{"2aw", "2bw", "3bw"},
-		{"0bw", "0cw", "1cw"}, {"0bw", "1bw", "0cw"},
-		{"1bw", "1cw", "2cw"}, {"1bw", "2bw", "1cw"},
-		{"2bw", "2cw", "3cw"}, {"2bw", "3bw", "2cw"},
-		{"3bw", "3cw", "4cw"},
-		{"0cw", "1cw", "0dw"},
-		{"1cw", "2cw", "1dw"}, {"1cw", "0dw", "1dw"},
-		{"2cw", "3cw", "2dw"}, {"2cw", "2dw", "3dw"},
-		{"3cw", "4cw", "3dw"}, {"3cw", "2dw", "3dw"},
-		{"4cw", "4dw", "5dw"},
-		{"0dw", "0ew", "1ew"}, {"0dw", "1dw", "0ew"},
-		{"1dw", "2dw", "1ew"},
-		{"2dw", "1ew", "2ew"}, {"2dw", "3dw", "2ew"},
-		{"3dw", "2ew", "3ew"}, {"3dw", "4dw", "3ew"},
-		{"4dw", "3ew", "4ew"}, {"4dw", "5dw", "3ew"},
-		{"0ew", "1ew", "0fw"},
-		{"1ew", "2ew", "1fw"}, {"1ew", "0fw", "1fw"},
-		{"2ew", "3ew", "2fw"}, {"2ew", "1fw", "2fw"},
-		{"3ew", "4ew", "3fw"}, {"3ew", "2fw", "3fw"},
-		{"0fw", "1fw", "0gw"},
-		{"1fw", "2fw", "1gw"}, {"1fw", "0gw", "1gw"},
-		{"2fw", "3fw", "2gw"}, {"2fw", "1gw", "2gw"},
-	}
-	return game
+	return createBundledGameType("large")
 }
 
 const (
@@ -194,6 +58,11 @@ const (
 //..........\.5//.5\\.6/.H...........a- b- c4 d5 e4 f- g-
 //...........H..\.3/.H...............a- b- c- d5 e- f- g-
 func printLargeGameToConsole(b *Board) {
+	// the layout only has room for the harbors on the harbor slots
+	if len(b.HarborDirections) > 0 {
+		printColumnsToConsole(b)
+		return
+	}
 	// 3 "a0", "g0"
 	// 6 "a1", "g1"
 	// 9 "a2", "g2"
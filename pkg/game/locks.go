@@ -0,0 +1,357 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/kennygrant/sanitize"
+)
+
+const (
+	// lockWildcard the character of a lock or a partial game code that is left to the generation
+	lockWildcard = "?"
+	// lockSeparator separates the locks of the positions
+	lockSeparator = ","
+	// lockPositionSeparator separates the position of a lock from what is locked
+	lockPositionSeparator = ":"
+	// lockElementSeparator separates the elements that are locked on the same position, such as number=8+harbor=0
+	lockElementSeparator = "+"
+)
+
+// TileLock the elements of a tile that are fixed on the board, as their codes in a game code
+// An empty element is not locked, and is generated like on any other tile
+type TileLock struct {
+	Landscape string
+	Number    string
+	Harbor    string
+}
+
+// Locks the tiles that are fixed on a board, keyed by their position, such as c2
+type Locks map[string]TileLock
+
+// ParseLocks parses the locks for a board of the game type, either as a list of positions with what is locked on them,
+// or as a partial game code in which every character that is not locked is a wildcard (?)
+// A position is locked as a tile of three characters of a game code, such as c2:6z6 for the desert in the centre,
+// with wildcards for the elements that are not locked, such as c2:3??, or by element, such as a0:harbor=0 or b1:number=8+harbor=grain
+// Returns an error when a lock can not be on the board, or when the locks together can not be satisfied
+func ParseLocks(value string, gameType GameType) (Locks, error) {
+	locks := Locks{}
+	if value == "" {
+		return locks, nil
+	}
+	if strings.Contains(value, lockPositionSeparator) {
+		for _, entry := range strings.Split(value, lockSeparator) {
+			position, elements, found := strings.Cut(strings.TrimSpace(entry), lockPositionSeparator)
+			if !found {
+				return nil, fmt.Errorf("lock %s has no position, expected a lock such as c2:6z6", sanitize.Name(entry))
+			}
+			lock, err := parseTileLock(elements)
+			if err != nil {
+				return nil, fmt.Errorf("lock of %s: %w", sanitize.Name(position), err)
+			}
+			if err := locks.add(position, lock); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		code := value
+		if strings.Count(code, DefaultGameRulesNormal.Delimiter) == len(gameType.BoardLayout) {
+			code = strings.ReplaceAll(code, DefaultGameRulesNormal.Delimiter, "")
+		}
+		positions := codePositions(gameType)
+		if len(code) != len(positions)*3 {
+			return nil, fmt.Errorf("a partial game code of %s has %d characters, not %d", gameType.Name, len(code), len(positions)*3)
+		}
+		for i, position := range positions {
+			lock, err := parseTileLock(code[i*3 : i*3+3])
+			if err != nil {
+				return nil, fmt.Errorf("lock of %s: %w", position, err)
+			}
+			if err := locks.add(position, lock); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := locks.verify(gameType); err != nil {
+		return nil, err
+	}
+	return locks, nil
+}
+
+// Matches returns whether every locked element is on the board
+func (locks Locks) Matches(board *Board) bool {
+	for position, lock := range locks {
+		column, row, ok := parsePosition(position)
+		if !ok || row >= len(board.Board[column]) {
+			return false
+		}
+		tile := board.Board[column][row]
+		if (lock.Landscape != "" && lock.Landscape != tile.Landscape.Code) ||
+			(lock.Number != "" && lock.Number != tile.Number.Code) ||
+			(lock.Harbor != "" && lock.Harbor != tile.Harbor.Code) {
+			return false
+		}
+	}
+	return true
+}
+
+// LocksNumbers returns whether a number is locked on any position, other than the empty number of a desert
+func (locks Locks) LocksNumbers() bool {
+	for _, lock := range locks {
+		if lock.Number != "" && lock.Number != model.NumberEmpty.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTileLock parses what is locked on a position, three characters of a game code or the locked elements by name
+func parseTileLock(value string) (TileLock, error) {
+	if !strings.Contains(value, "=") {
+		if len(value) != 3 {
+			return TileLock{}, fmt.Errorf("%s is not a tile of a game code, such as 6z6, or a list of elements, such as harbor=0", sanitize.Name(value))
+		}
+		lock := TileLock{Landscape: value[0:1], Number: value[1:2], Harbor: value[2:3]}
+		for _, element := range []*string{&lock.Landscape, &lock.Number, &lock.Harbor} {
+			if *element == lockWildcard {
+				*element = ""
+			}
+		}
+		if lock.Landscape != "" && !isLandscapeCode(lock.Landscape) {
+			return TileLock{}, fmt.Errorf("%s is not a landscape code", sanitize.Name(lock.Landscape))
+		}
+		if _, ok := model.Numbers[lock.Number]; lock.Number != "" && !ok {
+			return TileLock{}, fmt.Errorf("%s is not a number code", sanitize.Name(lock.Number))
+		}
+		if _, ok := model.Harbors[lock.Harbor]; lock.Harbor != "" && !ok {
+			return TileLock{}, fmt.Errorf("%s is not a harbor code", sanitize.Name(lock.Harbor))
+		}
+		return lock, nil
+	}
+
+	var lock TileLock
+	for _, element := range strings.Split(value, lockElementSeparator) {
+		name, elementValue, _ := strings.Cut(element, "=")
+		var err error
+		switch strings.ToLower(name) {
+		case "landscape":
+			lock.Landscape, err = landscapeLockCode(elementValue)
+		case "number":
+			lock.Number, err = numberLockCode(elementValue)
+		case "harbor":
+			lock.Harbor, err = harborLockCode(elementValue)
+		default:
+			err = fmt.Errorf("unknown element %s, expected one of landscape, number or harbor", sanitize.Name(name))
+		}
+		if err != nil {
+			return TileLock{}, err
+		}
+	}
+	return lock, nil
+}
+
+// landscapeLockCode returns the code of a landscape, given by its code or its name, such as 6 or desert
+func landscapeLockCode(value string) (string, error) {
+	if isLandscapeCode(value) {
+		return value, nil
+	}
+	for code, landscape := range model.Landscapes {
+		if strings.EqualFold(landscape.Name, value) {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a landscape", sanitize.Name(value))
+}
+
+// numberLockCode returns the code of a number, given by its code or its value, such as f or 8
+func numberLockCode(value string) (string, error) {
+	if _, ok := model.Numbers[value]; ok {
+		return value, nil
+	}
+	if number, err := strconv.Atoi(value); err == nil {
+		if found := findNumber(number); found != nil {
+			return found.Code, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a number", sanitize.Name(value))
+}
+
+// harborLockCode returns the code of a harbor, given by its code or its resource, such as 0 or all
+func harborLockCode(value string) (string, error) {
+	if _, ok := model.Harbors[value]; ok {
+		return value, nil
+	}
+	if strings.EqualFold(value, model.None.Name) {
+		return model.HarborNone.Code, nil
+	}
+	if harbor := findHarbor(value); harbor != nil {
+		return harbor.Code, nil
+	}
+	return "", fmt.Errorf("%s is not a harbor", sanitize.Name(value))
+}
+
+func isLandscapeCode(code string) bool {
+	_, ok := model.Landscapes[code]
+	return ok
+}
+
+// add adds the lock of a position, an element that is locked twice has to be locked to the same code
+func (locks Locks) add(position string, lock TileLock) error {
+	existing := locks[position]
+	for _, element := range []struct {
+		name     string
+		existing *string
+		lock     string
+	}{
+		{"landscape", &existing.Landscape, lock.Landscape},
+		{"number", &existing.Number, lock.Number},
+		{"harbor", &existing.Harbor, lock.Harbor},
+	} {
+		if element.lock == "" {
+			continue
+		}
+		if *element.existing != "" && *element.existing != element.lock {
+			return fmt.Errorf("the %s of %s is locked twice", element.name, sanitize.Name(position))
+		}
+		*element.existing = element.lock
+	}
+	locks[position] = existing
+	return nil
+}
+
+// verify checks the locks against the game type, and completes them: a locked desert has no number, and a position
+// without a number is a desert
+// The Sea tiles can only be locked as Sea, those locks are dropped as the Sea tiles are fixed anyway
+func (locks Locks) verify(gameType GameType) error {
+	seaPositions := make(map[string]bool)
+	for _, position := range gameType.SeaLayout {
+		seaPositions[position] = true
+	}
+	harborPositions := make(map[string]bool)
+	for _, position := range gameType.HarborLayout {
+		harborPositions[position] = true
+	}
+
+	for position, lock := range locks {
+		column, row, ok := parsePosition(position)
+		if !ok || row >= gameType.BoardLayout[column] {
+			return fmt.Errorf("position %s is not on the board of %s", sanitize.Name(position), gameType.Name)
+		}
+		if seaPositions[position] {
+			if (lock.Landscape != "" && lock.Landscape != model.Sea.Code) ||
+				(lock.Number != "" && lock.Number != model.NumberEmpty.Code) ||
+				(lock.Harbor != "" && lock.Harbor != model.HarborNone.Code) {
+				return fmt.Errorf("position %s is a Sea tile", position)
+			}
+			delete(locks, position)
+			continue
+		}
+
+		switch {
+		case lock.Landscape == model.Sea.Code:
+			return fmt.Errorf("position %s is not a Sea tile", position)
+		case lock.Landscape == model.Desert.Code && lock.Number != "" && lock.Number != model.NumberEmpty.Code:
+			return fmt.Errorf("the desert on %s can not have a number", position)
+		case lock.Landscape != "" && lock.Landscape != model.Desert.Code && lock.Number == model.NumberEmpty.Code:
+			return fmt.Errorf("the landscape on %s needs a number", position)
+		case lock.Landscape == model.Desert.Code:
+			lock.Number = model.NumberEmpty.Code
+		case lock.Number == model.NumberEmpty.Code:
+			lock.Landscape = model.Desert.Code
+		}
+		if lock.Harbor != "" && lock.Harbor != model.HarborNone.Code && !harborPositions[position] {
+			return fmt.Errorf("position %s has no harbor slot", position)
+		}
+		if lock.Harbor == model.HarborNone.Code && harborPositions[position] {
+			return fmt.Errorf("the harbor slot %s needs a harbor", position)
+		}
+		if lock.Harbor == model.HarborNone.Code {
+			// the other positions never have a harbor
+			lock.Harbor = ""
+		}
+		if lock == (TileLock{}) {
+			delete(locks, position)
+			continue
+		}
+		locks[position] = lock
+	}
+
+	if err := verifyLockedCodes("landscape", landscapeCodePool(gameType), locks.codes(func(lock TileLock) string { return lock.Landscape })); err != nil {
+		return err
+	}
+	numbers := locks.codes(func(lock TileLock) string {
+		if lock.Number == model.NumberEmpty.Code {
+			return ""
+		}
+		return lock.Number
+	})
+	if err := verifyLockedCodes("number", numberCodePool(gameType), numbers); err != nil {
+		return err
+	}
+	if err := verifyLockedCodes("harbor", harborCodePool(gameType), locks.codes(func(lock TileLock) string { return lock.Harbor })); err != nil {
+		return err
+	}
+
+	// the deserts that are not locked can only go to positions of which the number is not locked
+	deserts := gameType.DesertCount
+	openPositions := 0
+	for _, position := range codePositions(gameType) {
+		lock := locks[position]
+		if lock.Landscape == model.Desert.Code {
+			deserts--
+		} else if lock.Landscape == "" && lock.Number == "" && !seaPositions[position] {
+			openPositions++
+		}
+	}
+	if deserts > openPositions {
+		return fmt.Errorf("there is no room for the %d deserts that are not locked, the numbers of the other positions are locked", deserts)
+	}
+	return nil
+}
+
+// codes returns the codes of an element of all locks, in the order of their positions
+func (locks Locks) codes(element func(lock TileLock) string) []string {
+	positions := make([]string, 0, len(locks))
+	for position := range locks {
+		positions = append(positions, position)
+	}
+	sort.Strings(positions)
+	codes := make([]string, 0, len(locks))
+	for _, position := range positions {
+		if code := element(locks[position]); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// verifyLockedCodes checks that the game type has every locked code as often as it is locked
+func verifyLockedCodes(element string, pool []string, locked []string) error {
+	if _, missing := withoutCodes(pool, locked); missing != "" {
+		return fmt.Errorf("the %s %s is locked more often than the game has it", element, missing)
+	}
+	return nil
+}
+
+// withoutCodes returns the pool without one occurrence of each of the codes, in the same order,
+// and the first code that is not in the pool, if any
+func withoutCodes(pool []string, codes []string) ([]string, string) {
+	remaining := append([]string(nil), pool...)
+	for _, code := range codes {
+		found := false
+		for i, candidate := range remaining {
+			if candidate == code {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return remaining, code
+		}
+	}
+	return remaining, ""
+}
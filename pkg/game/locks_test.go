@@ -0,0 +1,114 @@
+package game
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocks(t *testing.T) {
+	locks, err := ParseLocks("c2:6z6,a0:harbor=0,b1:number=8+landscape=field,d0:5??", NormalGame)
+	assert.NoError(t, err)
+	assert.Equal(t, Locks{
+		"c2": {Landscape: "6", Number: "z"},
+		"a0": {Harbor: "0"},
+		"b1": {Landscape: "3", Number: "f"},
+		"d0": {Landscape: "5"},
+	}, locks)
+
+	locks, err = ParseLocks("", NormalGame)
+	assert.NoError(t, err)
+	assert.Empty(t, locks)
+}
+
+func TestParseLocksPartialGameCode(t *testing.T) {
+	code := strings.Repeat("???", 9) + "6z6" + strings.Repeat("???", 9)
+	locks, err := ParseLocks(code, NormalGame)
+	assert.NoError(t, err)
+	assert.Equal(t, Locks{"c2": {Landscape: "6", Number: "z"}}, locks)
+
+	withDelimiters := "?????????_????????????_??????6z6??????_????????????_?????????_"
+	locks, err = ParseLocks(withDelimiters, NormalGame)
+	assert.NoError(t, err)
+	assert.Equal(t, Locks{"c2": {Landscape: "6", Number: "z"}}, locks)
+
+	// the Sea tiles of a partial code are not locked, they are fixed anyway
+	locks, err = ParseLocks(seafarersGameCode[:3]+strings.Repeat("?", len(seafarersGameCode)-3), CreateSeafarersFourIslandsGame())
+	assert.NoError(t, err)
+	assert.Len(t, locks, 1)
+}
+
+func TestParseLocksInvalid(t *testing.T) {
+	tests := map[string]string{
+		"f0:6z6":                  "not on the board",
+		"c2":                      "partial game code",
+		"c2:6a6":                  "can not have a number",
+		"c2:1z6":                  "needs a number",
+		"c2:harbor=0":             "has no harbor slot",
+		"a0:harbor=none":          "needs a harbor",
+		"c2:6z6,c3:6z6":           "the landscape 6 is locked more often",
+		"a0:number=2,a1:number=2": "the number a is locked more often",
+		"c2:number=8,c2:number=6": "locked twice",
+		"c2:colour=red":           "unknown element colour",
+		"c2:abc":                  "not a landscape code",
+		"c2:number=7":             "7 is not a number",
+		"c2:landscape=sea":        "is not a Sea tile",
+		"a0:harbor=0,a1:harbor=0,a2:harbor=0,b3:harbor=0,c0:harbor=0": "the harbor 0 is locked more often",
+	}
+	for value, expected := range tests {
+		_, err := ParseLocks(value, NormalGame)
+		assert.ErrorContains(t, err, expected, value)
+	}
+}
+
+func TestParseLocksWithoutRoomForTheDesert(t *testing.T) {
+	numbers := []string{"a", "b", "b", "c", "c", "d", "d", "e", "e", "f", "f", "g", "g", "h", "h", "i", "i", "j"}
+	positions := codePositions(NormalGame)
+	locks := make([]string, 0, len(positions))
+	for i, number := range numbers {
+		locks = append(locks, positions[i]+":?"+number+"?")
+	}
+	_, err := ParseLocks(strings.Join(locks, ","), NormalGame)
+	assert.NoError(t, err)
+
+	// the only position without a locked number is locked as a forest
+	locks = append(locks, positions[len(positions)-1]+":landscape=forest")
+	_, err = ParseLocks(strings.Join(locks, ","), NormalGame)
+	assert.ErrorContains(t, err, "no room for the 1 deserts")
+}
+
+func TestGenerateLockedGameCode(t *testing.T) {
+	for _, key := range DefinedGames.Keys() {
+		definedGame := DefinedGames[key]
+		gameType := definedGame.GameType
+		position := gameType.HarborLayout[0]
+		locks, err := ParseLocks(position+":4e0,"+gameType.HarborLayout[1]+":harbor=0", gameType)
+		if !assert.NoError(t, err, key) {
+			continue
+		}
+		for seed := int64(1); seed <= 20; seed++ {
+			code := GenerateLockedGameCode(gameType, locks, rand.New(rand.NewSource(seed)))
+			board, err := definedGame.Inflate(code)
+			if assert.NoError(t, err, key) {
+				assert.True(t, locks.Matches(&board), key)
+				assert.True(t, matchesTileCounts(&board, &gameType), key)
+			}
+		}
+	}
+}
+
+func TestGenerateLockedGameCodeMovesTheDesert(t *testing.T) {
+	// every position except the last has a locked number, so the desert has to go to the last position
+	numbers := []string{"a", "b", "b", "c", "c", "d", "d", "e", "e", "f", "f", "g", "g", "h", "h", "i", "i", "j"}
+	positions := codePositions(NormalGame)
+	locks := Locks{}
+	for i, number := range numbers {
+		locks[positions[i]] = TileLock{Number: number}
+	}
+	for seed := int64(1); seed <= 20; seed++ {
+		code := GenerateLockedGameCode(NormalGame, locks, rand.New(rand.NewSource(seed)))
+		assert.Equal(t, "6z", code[len(code)-3:len(code)-1])
+	}
+}
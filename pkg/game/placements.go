@@ -0,0 +1,417 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// PlacementStrategy how the bots of a draft pick their settlements
+type PlacementStrategy string
+
+const (
+	// PlacementGreedy each seat picks the intersection that is worth the most to it right now
+	PlacementGreedy PlacementStrategy = "greedy"
+	// PlacementLookahead each seat tries its best intersections, plays out the rest of the draft greedily for each,
+	// and picks the one that leaves it with the most value at the end of the draft
+	PlacementLookahead PlacementStrategy = "lookahead"
+)
+
+// PlacementStrategies the strategies the bots of a draft can use
+var PlacementStrategies = []PlacementStrategy{PlacementGreedy, PlacementLookahead}
+
+const (
+	// MinPlayers and MaxPlayers the number of players a draft can be simulated for
+	MinPlayers = 3
+	MaxPlayers = 6
+	// StandardPlayers the number of players of the standard game
+	StandardPlayers = 4
+	// settlementsPerSeat the settlements every seat places before the game starts
+	settlementsPerSeat = 2
+	// recommendedPlacements the number of intersections that are recommended
+	recommendedPlacements = 10
+	// lookaheadCandidates the number of intersections the lookahead strategy plays out
+	lookaheadCandidates = 6
+	// diversityValue the value, in pips, of a resource a seat does not produce yet
+	diversityValue = 2
+	// harborValue the value, in pips, of a harbor a seat can use
+	harborValue = 1
+)
+
+// PlacementCandidate an intersection to place a settlement on, with the number of pips and the resources it produces
+type PlacementCandidate struct {
+	Intersection int
+	Tiles        []string
+	Pips         int
+	Score        int
+	Resources    map[string]int
+	Harbor       string
+}
+
+// PlacementPick a settlement a seat placed during the draft, Round is 1 for the first settlement and 2 for the second
+type PlacementPick struct {
+	Seat         int
+	Round        int
+	Intersection int
+	Pips         int
+}
+
+// SeatPlacement the settlements of a seat after the draft, the tiles they touch, and what they produce
+// Pips is the number of dice combinations that produce a resource for the seat, ExpectedPerRoll the resources per roll,
+// and Diversity the number of different resources the seat produces
+type SeatPlacement struct {
+	Seat            int
+	Intersections   []int
+	Tiles           []string
+	Pips            int
+	Score           int
+	ExpectedPerRoll float64
+	Resources       map[string]int
+	Diversity       int
+	Harbors         []string
+}
+
+// PlacementDraft the outcome of the draft of the opening settlements
+// FirstSeatAdvantage is how many pips the first seat has above the average of the other seats,
+// and SeatGap how many pips the best seat has above the worst seat
+type PlacementDraft struct {
+	Players            int
+	Strategy           PlacementStrategy
+	Recommendations    []PlacementCandidate
+	Picks              []PlacementPick
+	Seats              []SeatPlacement
+	FirstSeatAdvantage int
+	SeatGap            int
+}
+
+// ParsePlacementStrategy returns the PlacementStrategy with the given name, an empty name is the PlacementGreedy
+func ParsePlacementStrategy(name string) (PlacementStrategy, bool) {
+	if name == "" {
+		return PlacementGreedy, true
+	}
+	for _, strategy := range PlacementStrategies {
+		if string(strategy) == name {
+			return strategy, true
+		}
+	}
+	return "", false
+}
+
+// draft the state of a draft, the candidates that can still be picked and the settlements of every seat
+type draft struct {
+	candidates []PlacementCandidate
+	neighbours map[int][]int
+	harbors    map[int]*model.Harbor
+	taken      map[int]bool
+	seats      [][]int
+	picks      []PlacementPick
+}
+
+// DraftPlacements simulates the snake draft of the opening settlements, in which the seats pick in the order 1, 2, .., n,
+// and then in reverse from n back to 1, and no two settlements can be on neighbouring intersections
+// The same board, number of players and strategy always result in the same draft
+func DraftPlacements(board *Board, players int, strategy PlacementStrategy) (PlacementDraft, error) {
+	if players < MinPlayers || players > MaxPlayers {
+		return PlacementDraft{}, fmt.Errorf("a draft is for %d to %d players, not %d", MinPlayers, MaxPlayers, players)
+	}
+	if _, ok := ParsePlacementStrategy(string(strategy)); !ok {
+		return PlacementDraft{}, fmt.Errorf("unknown placement strategy %s", strategy)
+	}
+
+	state := newDraft(board, players)
+	for _, seat := range snakeOrder(players) {
+		var pick int
+		if strategy == PlacementLookahead {
+			pick = state.lookaheadPick(seat)
+		} else {
+			pick = state.greedyPick(seat)
+		}
+		if pick < 0 {
+			break
+		}
+		state.place(seat, pick)
+	}
+
+	result := PlacementDraft{
+		Players:         players,
+		Strategy:        strategy,
+		Recommendations: state.recommendations(),
+		Picks:           state.picks,
+		Seats:           make([]SeatPlacement, 0, players),
+	}
+	others := 0
+	for seat := range state.seats {
+		placement := state.seatPlacement(seat)
+		result.Seats = append(result.Seats, placement)
+		if seat > 0 {
+			others += placement.Pips
+		}
+	}
+	result.FirstSeatAdvantage = result.Seats[0].Pips - others/(players-1)
+	result.SeatGap = result.BestSeat().Pips - result.WorstSeat().Pips
+	return result, nil
+}
+
+// BestSeat and WorstSeat return the seats with the most and the fewest pips, the first of them in seat order
+func (draft PlacementDraft) BestSeat() SeatPlacement {
+	best := draft.Seats[0]
+	for _, seat := range draft.Seats {
+		if seat.Pips > best.Pips {
+			best = seat
+		}
+	}
+	return best
+}
+
+func (draft PlacementDraft) WorstSeat() SeatPlacement {
+	worst := draft.Seats[0]
+	for _, seat := range draft.Seats {
+		if seat.Pips < worst.Pips {
+			worst = seat
+		}
+	}
+	return worst
+}
+
+// DraftPlayers returns the number of players of the rules, or the StandardPlayers when it is not a number a draft is for
+func DraftPlayers(rules GameRules) int {
+	if rules.Players < MinPlayers || rules.Players > MaxPlayers {
+		return StandardPlayers
+	}
+	return rules.Players
+}
+
+func newDraft(board *Board, players int) *draft {
+	intersections, roads := board.Graph()
+	state := &draft{
+		candidates: make([]PlacementCandidate, len(intersections)),
+		neighbours: make(map[int][]int, len(intersections)),
+		harbors:    make(map[int]*model.Harbor),
+		taken:      make(map[int]bool),
+		seats:      make([][]int, players),
+	}
+	for _, intersection := range intersections {
+		candidate := PlacementCandidate{
+			Intersection: intersection.ID,
+			Tiles:        intersection.Tiles,
+			Pips:         intersection.Pips,
+			Score:        intersection.Score,
+			Resources:    intersection.Resources,
+		}
+		if intersection.Harbor != nil {
+			candidate.Harbor = intersection.Harbor.Name
+			state.harbors[intersection.ID] = intersection.Harbor
+		}
+		state.candidates[intersection.ID] = candidate
+	}
+	for _, road := range roads {
+		a, b := road.Intersections[0], road.Intersections[1]
+		state.neighbours[a] = append(state.neighbours[a], b)
+		state.neighbours[b] = append(state.neighbours[b], a)
+	}
+	return state
+}
+
+// snakeOrder returns the seat of every pick of the draft, such as 0, 1, 2, 3, 3, 2, 1, 0 for four players
+func snakeOrder(players int) []int {
+	order := make([]int, 0, players*settlementsPerSeat)
+	for seat := 0; seat < players; seat++ {
+		order = append(order, seat)
+	}
+	for seat := players - 1; seat >= 0; seat-- {
+		order = append(order, seat)
+	}
+	return order
+}
+
+// available returns whether a settlement can still be placed on the intersection
+func (state *draft) available(id int) bool {
+	if state.taken[id] || state.candidates[id].Pips == 0 {
+		return false
+	}
+	for _, neighbour := range state.neighbours[id] {
+		if state.taken[neighbour] {
+			return false
+		}
+	}
+	return true
+}
+
+// value what the intersection is worth to the seat, the pips plus a bonus for each resource the seat does not produce yet,
+// and for a harbor that trades all resources or a resource the seat would produce
+func (state *draft) value(seat int, id int) int {
+	candidate := state.candidates[id]
+	produced := state.produced(seat)
+	value := candidate.Pips
+	for resource, pips := range candidate.Resources {
+		if pips > 0 && produced[resource] == 0 {
+			value += diversityValue
+		}
+	}
+	if harbor, ok := state.harbors[id]; ok {
+		if harbor.Resource == *model.All || produced[harbor.Resource.Name] > 0 || candidate.Resources[harbor.Resource.Name] > 0 {
+			value += harborValue
+		}
+	}
+	return value
+}
+
+// produced returns the pips per resource of the settlements of the seat
+func (state *draft) produced(seat int) map[string]int {
+	produced := make(map[string]int)
+	for _, id := range state.seats[seat] {
+		for resource, pips := range state.candidates[id].Resources {
+			produced[resource] += pips
+		}
+	}
+	return produced
+}
+
+// ranked returns the available intersections by their value to the seat, highest first, and by ID for the same value
+func (state *draft) ranked(seat int) []int {
+	ranked := make([]int, 0, len(state.candidates))
+	values := make(map[int]int, len(state.candidates))
+	for id := range state.candidates {
+		if state.available(id) {
+			ranked = append(ranked, id)
+			values[id] = state.value(seat, id)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return values[ranked[i]] > values[ranked[j]]
+	})
+	return ranked
+}
+
+// greedyPick returns the intersection with the highest value to the seat, or -1 when there is none left
+func (state *draft) greedyPick(seat int) int {
+	ranked := state.ranked(seat)
+	if len(ranked) == 0 {
+		return -1
+	}
+	return ranked[0]
+}
+
+// lookaheadPick plays out the rest of the draft greedily for each of the best intersections of the seat,
+// and returns the one that leaves the seat with the highest total value
+func (state *draft) lookaheadPick(seat int) int {
+	ranked := state.ranked(seat)
+	if len(ranked) == 0 {
+		return -1
+	}
+	if len(ranked) > lookaheadCandidates {
+		ranked = ranked[:lookaheadCandidates]
+	}
+	best, bestValue := ranked[0], -1
+	remaining := snakeOrder(len(state.seats))[len(state.picks)+1:]
+	for _, candidate := range ranked {
+		playout := state.copy()
+		playout.place(seat, candidate)
+		for _, next := range remaining {
+			if pick := playout.greedyPick(next); pick >= 0 {
+				playout.place(next, pick)
+			}
+		}
+		if value := playout.seatValue(seat); value > bestValue {
+			best, bestValue = candidate, value
+		}
+	}
+	return best
+}
+
+// seatValue the value of all settlements of the seat together, the pips plus a bonus for every resource it produces,
+// and for every harbor it can use
+func (state *draft) seatValue(seat int) int {
+	produced := state.produced(seat)
+	value := 0
+	for _, pips := range produced {
+		value += pips + diversityValue
+	}
+	for _, id := range state.seats[seat] {
+		if harbor, ok := state.harbors[id]; ok && (harbor.Resource == *model.All || produced[harbor.Resource.Name] > 0) {
+			value += harborValue
+		}
+	}
+	return value
+}
+
+func (state *draft) place(seat int, id int) {
+	state.taken[id] = true
+	state.seats[seat] = append(state.seats[seat], id)
+	state.picks = append(state.picks, PlacementPick{
+		Seat:         seat + 1,
+		Round:        len(state.seats[seat]),
+		Intersection: id,
+		Pips:         state.candidates[id].Pips,
+	})
+}
+
+func (state *draft) copy() *draft {
+	copied := &draft{
+		candidates: state.candidates,
+		neighbours: state.neighbours,
+		harbors:    state.harbors,
+		taken:      make(map[int]bool, len(state.taken)),
+		seats:      make([][]int, len(state.seats)),
+		picks:      append([]PlacementPick{}, state.picks...),
+	}
+	for id := range state.taken {
+		copied.taken[id] = true
+	}
+	for seat, ids := range state.seats {
+		copied.seats[seat] = append([]int{}, ids...)
+	}
+	return copied
+}
+
+// recommendations returns the intersections with the highest value on an empty board
+func (state *draft) recommendations() []PlacementCandidate {
+	empty := newEmptyDraft(state)
+	ranked := empty.ranked(0)
+	if len(ranked) > recommendedPlacements {
+		ranked = ranked[:recommendedPlacements]
+	}
+	recommendations := make([]PlacementCandidate, 0, len(ranked))
+	for _, id := range ranked {
+		recommendations = append(recommendations, state.candidates[id])
+	}
+	return recommendations
+}
+
+func newEmptyDraft(state *draft) *draft {
+	return &draft{
+		candidates: state.candidates,
+		neighbours: state.neighbours,
+		harbors:    state.harbors,
+		taken:      make(map[int]bool),
+		seats:      make([][]int, len(state.seats)),
+	}
+}
+
+func (state *draft) seatPlacement(seat int) SeatPlacement {
+	placement := SeatPlacement{
+		Seat:          seat + 1,
+		Intersections: state.seats[seat],
+		Tiles:         make([]string, 0),
+		Resources:     state.produced(seat),
+		Harbors:       make([]string, 0),
+	}
+	for _, id := range state.seats[seat] {
+		candidate := state.candidates[id]
+		placement.Tiles = appendMissing(placement.Tiles, candidate.Tiles...)
+		placement.Pips += candidate.Pips
+		placement.Score += candidate.Score
+		if candidate.Harbor != "" {
+			placement.Harbors = append(placement.Harbors, candidate.Harbor)
+		}
+	}
+	placement.ExpectedPerRoll = math.Round(float64(placement.Pips)/36*1000) / 1000
+	for _, pips := range placement.Resources {
+		if pips > 0 {
+			placement.Diversity++
+		}
+	}
+	return placement
+}
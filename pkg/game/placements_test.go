@@ -0,0 +1,132 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeOrder(t *testing.T) {
+	assert.Equal(t, []int{0, 1, 2, 3, 3, 2, 1, 0}, snakeOrder(4))
+	assert.Equal(t, []int{0, 1, 2, 2, 1, 0}, snakeOrder(3))
+}
+
+func TestDraftPlacements(t *testing.T) {
+	board, err := InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0")
+	assert.NoError(t, err)
+	intersections, roads := board.Graph()
+	neighbours := make(map[int][]int)
+	for _, road := range roads {
+		neighbours[road.Intersections[0]] = append(neighbours[road.Intersections[0]], road.Intersections[1])
+		neighbours[road.Intersections[1]] = append(neighbours[road.Intersections[1]], road.Intersections[0])
+	}
+
+	for _, strategy := range PlacementStrategies {
+		for players := MinPlayers; players <= MaxPlayers; players++ {
+			draft, err := DraftPlacements(&board, players, strategy)
+			assert.NoError(t, err)
+			assert.Len(t, draft.Seats, players)
+			assert.Len(t, draft.Picks, players*settlementsPerSeat)
+
+			taken := make(map[int]bool)
+			for _, pick := range draft.Picks {
+				assert.False(t, taken[pick.Intersection], "picked twice: %d", pick.Intersection)
+				for _, neighbour := range neighbours[pick.Intersection] {
+					assert.False(t, taken[neighbour], "picked next to another settlement: %d", pick.Intersection)
+				}
+				taken[pick.Intersection] = true
+				assert.Equal(t, intersections[pick.Intersection].Pips, pick.Pips)
+			}
+
+			others := 0
+			for _, seat := range draft.Seats[1:] {
+				others += seat.Pips
+			}
+			assert.Equal(t, draft.Seats[0].Pips-others/(players-1), draft.FirstSeatAdvantage)
+		}
+	}
+}
+
+func TestDraftPlacementsSnakeOrder(t *testing.T) {
+	board, err := InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0")
+	assert.NoError(t, err)
+	draft, err := DraftPlacements(&board, 4, PlacementGreedy)
+	assert.NoError(t, err)
+
+	seats := make([]int, 0)
+	for _, pick := range draft.Picks {
+		seats = append(seats, pick.Seat)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 4, 3, 2, 1}, seats)
+	// the first pick of a greedy draft is the best recommendation
+	assert.Equal(t, draft.Recommendations[0].Intersection, draft.Picks[0].Intersection)
+	assert.Len(t, draft.Recommendations, recommendedPlacements)
+	assert.Equal(t, 22, draft.Seats[0].Pips)
+	assert.Equal(t, 4, draft.Seats[0].Diversity)
+}
+
+func TestDraftPlacementsIsDeterministic(t *testing.T) {
+	board, err := InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0")
+	assert.NoError(t, err)
+	draftA, _ := DraftPlacements(&board, 5, PlacementLookahead)
+	draftB, _ := DraftPlacements(&board, 5, PlacementLookahead)
+	assert.Equal(t, draftA, draftB)
+}
+
+func TestDraftPlacementsRejectsPlayers(t *testing.T) {
+	board, err := InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0")
+	assert.NoError(t, err)
+	_, err = DraftPlacements(&board, 2, PlacementGreedy)
+	assert.Error(t, err)
+	_, err = DraftPlacements(&board, 4, PlacementStrategy("random"))
+	assert.Error(t, err)
+}
+
+func TestValidateFirstSeatAdvantage(t *testing.T) {
+	board, err := InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0")
+	assert.NoError(t, err)
+
+	rules := DefaultGameRulesNormal
+	assert.True(t, ValidateFirstSeatAdvantage(&board, rules).Valid)
+
+	rules.MaxFirstSeatAdvantage = 1
+	assert.True(t, ValidateFirstSeatAdvantage(&board, rules).Valid)
+
+	// the same board with two numbers swapped, which favours the first seat
+	board, err = InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65e63b61g56z04c12i0")
+	assert.NoError(t, err)
+	rules.MaxFirstSeatAdvantage = 4
+	result := ValidateFirstSeatAdvantage(&board, rules)
+	assert.False(t, result.Valid)
+	assert.Equal(t, 5, result.Value)
+	assert.Equal(t, 4, result.Threshold)
+	assert.NotEmpty(t, result.Positions)
+}
+
+func TestDraftPlayers(t *testing.T) {
+	assert.Equal(t, 4, DraftPlayers(DefaultGameRulesNormal))
+	assert.Equal(t, 6, DraftPlayers(DefaultGameRulesLarge))
+	assert.Equal(t, StandardPlayers, DraftPlayers(GameRules{}))
+	assert.Equal(t, StandardPlayers, DraftPlayers(GameRules{Players: 7}))
+}
+
+func TestValidateSeatGap(t *testing.T) {
+	board, err := InflateGameFromCode("2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0")
+	assert.NoError(t, err)
+
+	rules := DefaultGameRulesNormal
+	assert.True(t, ValidateSeatGap(&board, rules).Valid)
+
+	// with four players the seats get 22, 22, 22 and 20 pips
+	rules.MaxSeatGap = 2
+	assert.True(t, ValidateSeatGap(&board, rules).Valid)
+
+	// with five players the seats get 20, 22, 21, 18 and 22 pips
+	rules.Players = 5
+	result := ValidateSeatGap(&board, rules)
+	assert.False(t, result.Valid)
+	assert.Equal(t, 4, result.Value)
+	assert.Equal(t, 2, result.Threshold)
+	assert.Equal(t, "seat 2 has 4 pips more than seat 4 of 5 players", result.Message)
+	assert.Subset(t, result.Positions, []string{"c1", "c2", "d1", "b2", "b3", "e0"})
+}
@@ -0,0 +1,214 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CodeInflater inflates a board from a v1 game code of one game type
+// Returns ErrUnrecognizableCode when the code is not of the game type, such as a code of a different length
+type CodeInflater func(code string) (Board, error)
+
+// DefinedGame a game type in the registry, with the default rules for generating its maps,
+// the inflater of its game codes and the printer of its boards to the console
+type DefinedGame struct {
+	GameType GameType
+	Rules    GameRules
+	Inflate  CodeInflater
+	Print    PrintBoardToConsole
+}
+
+// Registry the game types, keyed by the name with which they are selected, such as normal or seafarers-new-shores
+type Registry map[string]DefinedGame
+
+// bundledGameKeys the keys of the game types that are bundled with the application, in the order they are listed
+var bundledGameKeys = []string{"normal", "large", SeafarersGameTypePrefix + "new-shores", SeafarersGameTypePrefix + "four-islands"}
+
+// gameTypeNumbers the keys of the game types that were selected by the number of the GameType in the GameRules
+var gameTypeNumbers = map[string]string{"0": "normal", "1": "large"}
+
+// DefinedGames the registry of all game types
+// Contains the bundled game types, and the game types loaded via LoadGameDefinitions
+var DefinedGames = Registry{
+	"normal": bundledGame(NormalGame, DefaultGameRulesNormal),
+	"large":  bundledGame(LargeGame, DefaultGameRulesLarge),
+	SeafarersGameTypePrefix + "new-shores": bundledGame(
+		SeafarersGames[SeafarersGameTypePrefix+"new-shores"], DefaultGameRulesSeafarers),
+	SeafarersGameTypePrefix + "four-islands": bundledGame(
+		SeafarersGames[SeafarersGameTypePrefix+"four-islands"], DefaultGameRulesSeafarers),
+}
+
+func bundledGame(gameType GameType, rules GameRules) DefinedGame {
+	return DefinedGame{GameType: gameType, Rules: rules, Inflate: inflaterForGameType(gameType), Print: gameType.ToConsole}
+}
+
+// Register adds a game type to the registry, its printer defaults to the ToConsole of the game type
+// The key and the code tag of the game type should not be used by any of the registered game types
+func (registry Registry) Register(key string, definedGame DefinedGame) error {
+	if _, exists := registry[key]; exists {
+		return fmt.Errorf("game type %s already exists", key)
+	}
+	if existing, exists := registry.KeyByCodeTag(definedGame.GameType.CodeTag); exists {
+		return fmt.Errorf("code tag %s is already used by game type %s", definedGame.GameType.CodeTag, existing)
+	}
+	if definedGame.Inflate == nil {
+		return fmt.Errorf("game type %s has no code inflater", key)
+	}
+	if definedGame.Print == nil {
+		definedGame.Print = definedGame.GameType.ToConsole
+	}
+	registry[key] = definedGame
+	return nil
+}
+
+// Lookup returns the key and the game type for a name, which is the key in any case, such as Large,
+// or the number of the GameType in the GameRules, 0 for the Normal game and 1 for the Large game
+func (registry Registry) Lookup(name string) (string, DefinedGame, bool) {
+	if definedGame, ok := registry[name]; ok {
+		return name, definedGame, true
+	}
+	if key, ok := gameTypeNumbers[name]; ok {
+		definedGame, ok := registry[key]
+		return key, definedGame, ok
+	}
+	for _, key := range registry.Keys() {
+		if strings.EqualFold(key, name) {
+			return key, registry[key], true
+		}
+	}
+	return "", DefinedGame{}, false
+}
+
+// ForRules returns the key and the game type the rules are for
+// The game type is identified by the GameTypeString, or else by the number of the GameType, and defaults to the Normal game
+func (registry Registry) ForRules(rules GameRules) (string, DefinedGame) {
+	if key, definedGame, ok := registry.Lookup(rules.GameTypeString); ok {
+		return key, definedGame
+	}
+	if key, definedGame, ok := registry.Lookup(strconv.Itoa(rules.GameType)); ok {
+		return key, definedGame
+	}
+	return "normal", registry["normal"]
+}
+
+// KeyOf returns the key of a game type, such as normal for the Normal game
+func (registry Registry) KeyOf(gameType GameType) (string, bool) {
+	for _, key := range registry.Keys() {
+		if registry[key].GameType.Name == gameType.Name {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// KeyByCodeTag returns the key of the game type with the code tag
+func (registry Registry) KeyByCodeTag(codeTag string) (string, bool) {
+	for _, key := range registry.Keys() {
+		if registry[key].GameType.CodeTag == codeTag {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// Keys returns the keys of the game types, the bundled game types first and the others in alphabetical order
+func (registry Registry) Keys() []string {
+	keys := make([]string, 0, len(registry))
+	for key := range registry {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		first, second := bundledGameIndex(keys[i]), bundledGameIndex(keys[j])
+		if first != second {
+			return first < second
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// Inflate inflates a board from a v1 game code, with the inflater of the first game type that recognizes the code
+// Returns ErrUnrecognizableCode when none of the game types recognizes the code
+func (registry Registry) Inflate(code string) (Board, error) {
+	var inflationError error
+	for _, key := range registry.Keys() {
+		board, err := registry[key].Inflate(code)
+		if err == nil {
+			return board, nil
+		}
+		if inflationError == nil && !errors.Is(err, ErrUnrecognizableCode) {
+			inflationError = err
+		}
+	}
+	if inflationError != nil {
+		return Board{}, inflationError
+	}
+	return Board{}, ErrUnrecognizableCode
+}
+
+// bundledGameIndex returns the position of a bundled game type in the listing, which is after them for other game types
+func bundledGameIndex(key string) int {
+	for i, bundled := range bundledGameKeys {
+		if bundled == key {
+			return i
+		}
+	}
+	return len(bundledGameKeys)
+}
+
+// inflaterForGameType returns the code inflater of a game type, which recognizes its codes by their length,
+// every tile takes three characters, optionally with a delimiter after every column,
+// and for boards with variable harbors followed by the directions of the harbors
+// Game types with Sea tiles only inflate codes with the Sea tiles in the positions of the game type
+func inflaterForGameType(gameType GameType) CodeInflater {
+	return func(code string) (Board, error) {
+		code, directions := splitHarborDirections(code)
+		codeLength := gameType.TilesCount * 3
+		if len(code) != codeLength && len(code) != codeLength+len(gameType.BoardLayout) {
+			return Board{}, ErrUnrecognizableCode
+		}
+		board, err := inflateGameFromCode(code, gameType.BoardLayout, &gameType)
+		if err != nil {
+			return Board{}, err
+		}
+		if gameType.SeaCount > 0 && !matchesSeaLayout(&board, gameType.SeaLayout) {
+			return Board{}, fmt.Errorf("Inflation error: the Sea tiles do not match the %s", gameType.Name)
+		}
+		if err := board.applyHarborDirections(directions); err != nil {
+			return Board{}, err
+		}
+		return board, nil
+	}
+}
+
+// inflaterForDefinedGame returns the code inflater of a game type loaded from a definition file
+// Besides the length of the code and the positions of the Sea tiles, it recognizes its codes by the number of tiles of each landscape,
+// as game types from different definitions can have the same layout
+func inflaterForDefinedGame(gameType GameType) CodeInflater {
+	inflate := inflaterForGameType(gameType)
+	return func(code string) (Board, error) {
+		board, err := inflate(code)
+		if err != nil {
+			return Board{}, ErrUnrecognizableCode
+		}
+		if !matchesTileCounts(&board, &gameType) {
+			return Board{}, ErrUnrecognizableCode
+		}
+		return board, nil
+	}
+}
+
+// InflateDefinedGameFromCode inflates a game from code, for any of the DefinedGames
+// The game type is recognized by the length of the code, the number of tiles of each landscape and the positions of the Sea tiles
+func InflateDefinedGameFromCode(code string) (Board, error) {
+	for _, key := range DefinedGames.Keys() {
+		board, err := inflaterForDefinedGame(DefinedGames[key].GameType)(code)
+		if err == nil {
+			return board, nil
+		}
+	}
+	return Board{}, errors.New("Inflation error: the code does not match any game type")
+}
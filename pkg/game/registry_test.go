@@ -0,0 +1,71 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	names := map[string]string{
+		"normal":               "normal",
+		"0":                    "normal",
+		"Large":                "large",
+		"1":                    "large",
+		"seafarers-new-shores": "seafarers-new-shores",
+	}
+	for name, expected := range names {
+		key, definedGame, ok := DefinedGames.Lookup(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, key)
+		assert.Equal(t, DefinedGames[expected].GameType.Name, definedGame.GameType.Name)
+		assert.NotNil(t, definedGame.Print)
+	}
+
+	_, _, ok := DefinedGames.Lookup("2")
+	assert.False(t, ok)
+}
+
+func TestRegistryForRules(t *testing.T) {
+	key, definedGame := DefinedGames.ForRules(DefaultGameRulesLarge)
+	assert.Equal(t, "large", key)
+	assert.Equal(t, 5000, definedGame.Rules.Generations)
+
+	key, _ = DefinedGames.ForRules(GameRules{GameTypeString: "seafarers-four-islands"})
+	assert.Equal(t, "seafarers-four-islands", key)
+
+	key, _ = DefinedGames.ForRules(GameRules{GameTypeString: "unknown"})
+	assert.Equal(t, "normal", key)
+}
+
+func TestRegistryInflate(t *testing.T) {
+	codes := map[string]string{
+		normalGameCode:    "Normal",
+		largeGameCode:     "Large",
+		seafarersGameCode: SeafarersGames[SeafarersGameTypePrefix+"four-islands"].Name,
+	}
+	for code, name := range codes {
+		board, err := DefinedGames.Inflate(code)
+		assert.NoError(t, err)
+		assert.Equal(t, name, board.GameType.Name)
+	}
+
+	_, err := DefinedGames.Inflate("abc")
+	assert.ErrorIs(t, err, ErrUnrecognizableCode)
+	_, err = DefinedGames.Inflate("9" + normalGameCode[1:])
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnrecognizableCode)
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := Registry{}
+	assert.NoError(t, registry.Register("normal", DefinedGame{GameType: NormalGame, Rules: DefaultGameRulesNormal, Inflate: inflaterForGameType(NormalGame)}))
+	assert.NotNil(t, registry["normal"].Print)
+
+	err := registry.Register("normal", registry["normal"])
+	assert.ErrorContains(t, err, "game type normal already exists")
+	err = registry.Register("other", registry["normal"])
+	assert.ErrorContains(t, err, "code tag N is already used by game type normal")
+	err = registry.Register("large", DefinedGame{GameType: LargeGame})
+	assert.ErrorContains(t, err, "has no code inflater")
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// SeafarersGameTypePrefix the prefix of the game type parameter for Seafarers scenarios, such as seafarers-new-shores
+const SeafarersGameTypePrefix = "seafarers-"
+
+// SeafarersGames the supported Seafarers scenarios, keyed by their game type parameter
+var SeafarersGames = map[string]GameType{
+	SeafarersGameTypePrefix + "new-shores":   CreateSeafarersNewShoresGame(),
+	SeafarersGameTypePrefix + "four-islands": CreateSeafarersFourIslandsGame(),
+}
+
+// CreateSeafarersNewShoresGame creates the Seafarers "Heading for New Shores" scenario for up to four players.
+// A main island in the west, and two small islands to discover in the east.
+// ...................~~~~...................
+// .............~~~~........~~~~.............
+// .......~~~~........Fo10........Hi.8.......
+// .~~~~........Fi.5..HWoo..~~~~........~~~~.
+// .......Fo.4..HOre..Hi.3........Pa12.......
+// .~~~~..HGra..Mo.5........~~~~........Fo.2.
+// .......Pa11........Fi.9........~~~~.......
+// .~~~~........Mo.6........~~~~........~~~~.
+// .......Fi.8........Pa.9........Go.6.......
+// .~~~~..H3:1..De..........~~~~........Go.3.
+// .......~~~~..HLum..Mo.4........~~~~.......
+// .............~~~~..HBri..Hi10.............
+// ...................~~~~..H3:1.............
+// ..........................................
+func CreateSeafarersNewShoresGame() GameType {
+	return createBundledGameType(SeafarersGameTypePrefix + "new-shores")
+}
+
+// CreateSeafarersFourIslandsGame creates the Seafarers "The Four Islands" scenario for up to four players.
+// Four islands of different size, separated by the sea.
+func CreateSeafarersFourIslandsGame() GameType {
+	return createBundledGameType(SeafarersGameTypePrefix + "four-islands")
+}
+
+// InflateSeafarersGameFromCode inflates a Seafarers game from code
+// All scenarios share the same layout, the scenario is recognized by the positions of the Sea tiles
+func InflateSeafarersGameFromCode(code string) (Board, error) {
+	scenarios := make([]string, 0, len(SeafarersGames))
+	for scenario := range SeafarersGames {
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Strings(scenarios)
+
+	for _, scenario := range scenarios {
+		gameType := SeafarersGames[scenario]
+		board, err := inflateGameFromCode(code, gameType.BoardLayout, &gameType)
+		if err != nil {
+			return Board{}, err
+		}
+		if matchesSeaLayout(&board, gameType.SeaLayout) {
+			return board, nil
+		}
+	}
+	return Board{}, errors.New("Inflation error: the Sea tiles do not match any Seafarers scenario")
+}
+
+func matchesSeaLayout(board *Board, seaLayout []string) bool {
+	seaPositions := make(map[string]bool)
+	for _, position := range seaLayout {
+		seaPositions[position] = true
+	}
+	for column, tiles := range board.Board {
+		for row, tile := range tiles {
+			isSea := tile.Landscape.Code == model.Sea.Code
+			if isSea != seaPositions[fmt.Sprintf("%s%d", column, row)] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -1,8 +1,8 @@
 package game
 
 import (
-	"strconv"
-	"strings"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/joostvdg/cmg/pkg/model"
@@ -11,7 +11,26 @@ import (
 
 // ValidateBoard function that validates certain attributes of a game Board
 // the validate function should compare the current board against the rules for the request game
-type ValidateBoard func(board *Board, gameRules GameRules) bool
+// A board can be partially filled while it is generated, positions without a tile (nil) are not placed yet,
+// and a rule that can only be judged on the whole board, such as a minimum, is only checked once every tile is placed
+type ValidateBoard func(board *Board, gameRules GameRules) ValidationResult
+
+// ValidationResult the outcome of validating a board against a single rule
+// For an invalid board, Positions lists the offending tiles, and Value is what was measured against the Threshold
+type ValidationResult struct {
+	Rule      string
+	Valid     bool
+	Positions []string
+	Value     int
+	Threshold int
+	Message   string
+}
+
+// ValidationReport the results of all Validations of a board, the board is only Valid if every result is
+type ValidationReport struct {
+	Valid   bool
+	Results []ValidationResult
+}
 
 var (
 	Validations = []ValidateBoard{
@@ -20,68 +39,116 @@ var (
 		ValidateTilesNumbers,
 		ValidateResourceSpread,
 		ValidateHarbors,
+		ValidateRedNumbers,
+		ValidateSameNumbers,
+		ValidateNumbersPerResource,
+		ValidateFirstSeatAdvantage,
+		ValidateSeatGap,
 	}
+
+	// regularResources the resources that are produced by the regular landscapes, in order of their code
+	regularResources = []*model.Resource{model.Lumber, model.Wool, model.Grain, model.Brick, model.Ore}
 )
 
+// Failures returns the results of the rules the board does not satisfy
+func (report ValidationReport) Failures() []ValidationResult {
+	failures := make([]ValidationResult, 0)
+	for _, result := range report.Results {
+		if !result.Valid {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Distance how far the measured value lies outside the threshold of the rule, 0 for a valid result
+// An invalid result is always at least 1 away
+func (result ValidationResult) Distance() int {
+	if result.Valid {
+		return 0
+	}
+	distance := result.Value - result.Threshold
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance == 0 {
+		return 1
+	}
+	return distance
+}
+
+// Distance how far the board is from satisfying all rules, the sum of the distances of the results
+func (report ValidationReport) Distance() int {
+	distance := 0
+	for _, result := range report.Results {
+		distance += result.Distance()
+	}
+	return distance
+}
+
+func validResult(rule string) ValidationResult {
+	return ValidationResult{Rule: rule, Valid: true}
+}
+
+func invalidResult(rule string, positions []string, value int, threshold int, message string, args ...interface{}) ValidationResult {
+	sort.Strings(positions)
+	return ValidationResult{
+		Rule:      rule,
+		Valid:     false,
+		Positions: positions,
+		Value:     value,
+		Threshold: threshold,
+		Message:   fmt.Sprintf(message, args...),
+	}
+}
+
 // ValidateResourceScores validates the scores of the resources
 // we derived the scores from the probability scores of the Number of the tile they're associated with
 // there is a maximum, and a minimum to validate, to make sure all resources fall within a certain distribution
-func ValidateResourceScores(board *Board, rules GameRules) bool {
+func ValidateResourceScores(board *Board, rules GameRules) ValidationResult {
+	const rule = "ResourceScores"
+	if !board.complete() {
+		return validResult(rule)
+	}
 	start := time.Now()
 	log.Debug(" > ValidateResourceScores start")
-	isValid := true
-	resourceCounts := make([]int, 6, 6)
-	resourceScores := make([]int, 6, 6)
-
-	resourceScores[0] = 0
-	resourceCounts[0] = 0
-	resourceScores[1] = 0
-	resourceCounts[1] = 0
-	resourceScores[2] = 0
-	resourceCounts[2] = 0
-	resourceScores[3] = 0
-	resourceCounts[3] = 0
-	resourceScores[4] = 0
-	resourceCounts[4] = 0
-	resourceScores[5] = 0
-	resourceCounts[5] = 0
-
-	for _, tile := range board.Tiles {
-		codeInt, _ := strconv.Atoi(tile.Landscape.Code)
-		codeInt-- // we don't use the All resource, which is 0
-		resourceScores[codeInt] = resourceScores[codeInt] + tile.Number.Score
-		resourceCounts[codeInt] = resourceCounts[codeInt] + 1
-	}
-
-	for resourceId, score := range resourceScores {
-		skipDesertId, _ := strconv.Atoi(model.None.Code)
-		skipDesertId-- // because we did the same in the resource scores/counts
-		if resourceId == skipDesertId {
-			// skip Desert tiles
-			continue
-		}
+	resourceScores := make(map[string]int)
+	resourcePositions := make(map[string][]string)
+
+	for _, position := range board.Positions() {
+		tile := board.tile(position)
+		// Desert, Sea and Gold Field tiles do not produce one of the regular resources, and are not checked below
+		resourceScores[tile.Landscape.Resource.Code] += tile.Number.Score
+		resourcePositions[tile.Landscape.Resource.Code] = append(resourcePositions[tile.Landscape.Resource.Code], position)
+	}
 
+	for _, resource := range regularResources {
+		positions := resourcePositions[resource.Code]
 		log.WithFields(log.Fields{
-			"score":          score,
-			"resourceId":     resourceId,
-			"resourceCounts": resourceCounts[resourceId],
+			"score":          resourceScores[resource.Code],
+			"resource":       resource.Name,
+			"resourceCounts": len(positions),
 		}).Debug("  - scoring for resource:")
 
-		if resourceCounts[resourceId] == 0 {
-			isValid = false
-			continue
+		if len(positions) == 0 {
+			return invalidResult(rule, positions, 0, rules.MinimumResourceScore, "there are no tiles for %s", resource.Name)
 		}
 
-		avgScore := score / resourceCounts[resourceId]
+		avgScore := resourceScores[resource.Code] / len(positions)
 		if avgScore > rules.MaximumResourceScore || avgScore < rules.MinimumResourceScore {
 			t := time.Now()
 			elapsed := t.Sub(start)
 			log.WithFields(log.Fields{
-				"resourceId": resourceId,
-				"avgScore":   avgScore,
-				"Duration":   elapsed,
+				"resource": resource.Name,
+				"avgScore": avgScore,
+				"Duration": elapsed,
 			}).Debug("  - Invalid scoring for resource:")
-			isValid = false
+			if avgScore > rules.MaximumResourceScore {
+				return invalidResult(rule, positions, avgScore, rules.MaximumResourceScore,
+					"the average score of %s is %d, above the maximum of %d", resource.Name, avgScore, rules.MaximumResourceScore)
+			}
+			return invalidResult(rule, positions, avgScore, rules.MinimumResourceScore,
+				"the average score of %s is %d, below the minimum of %d", resource.Name, avgScore, rules.MinimumResourceScore)
 		}
 	}
 
@@ -90,7 +157,7 @@ func ValidateResourceScores(board *Board, rules GameRules) bool {
 	log.WithFields(log.Fields{
 		"Duration": elapsed,
 	}).Debug(" < ValidateResourceScores finish")
-	return isValid
+	return validResult(rule)
 }
 
 // ValidateAdjacentTiles validates the scores based on the tiles that lie next to each other
@@ -98,17 +165,25 @@ func ValidateResourceScores(board *Board, rules GameRules) bool {
 // we do not want a too great a spot, we also do not want a too weak spot
 // in addition, we also do not want too many spots (3 adjacent tiles) to be over a score of 300
 // as this would signify a skewed distribution of resources and their scores
-func ValidateAdjacentTiles(board *Board, rules GameRules) bool {
+func ValidateAdjacentTiles(board *Board, rules GameRules) ValidationResult {
+	const rule = "AdjacentTiles"
 	start := time.Now()
 	log.WithFields(log.Fields{
 		"number of tile groups": len(board.GameType.AdjacentTileGroups),
 	}).Debug(" > ValidateAdjacentTiles start")
 
 	scoresOver300 := 0
+	positionsOver300 := make([]string, 0)
 	for _, tileGroup := range board.GameType.AdjacentTileGroups {
-		valid, weightTotal := board.validateAdjectTileGroup(rules.MaximumScore, rules.MinimumScore, tileGroup[0], tileGroup[1], tileGroup[2])
+		// the score of a group only grows as its tiles are placed, so only its maximum can be checked before that
+		minimumScore := rules.MinimumScore
+		if !board.placed(tileGroup...) {
+			minimumScore = 0
+		}
+		valid, weightTotal := board.validateAdjectTileGroup(rules.MaximumScore, minimumScore, tileGroup[0], tileGroup[1], tileGroup[2])
 		if weightTotal > 300 {
 			scoresOver300++
+			positionsOver300 = appendMissing(positionsOver300, tileGroup...)
 		}
 
 		log.WithFields(log.Fields{
@@ -124,7 +199,13 @@ func ValidateAdjacentTiles(board *Board, rules GameRules) bool {
 			log.WithFields(log.Fields{
 				"Duration": elapsed,
 			}).Debug(" < ValidateAdjacentTiles finish")
-			return false
+			positions := append([]string{}, tileGroup...)
+			if weightTotal > rules.MaximumScore {
+				return invalidResult(rule, positions, weightTotal, rules.MaximumScore,
+					"the tiles %v have a score of %d, above the maximum of %d", tileGroup, weightTotal, rules.MaximumScore)
+			}
+			return invalidResult(rule, positions, weightTotal, rules.MinimumScore,
+				"the tiles %v have a score of %d, below the minimum of %d", tileGroup, weightTotal, rules.MinimumScore)
 		}
 	}
 
@@ -134,54 +215,87 @@ func ValidateAdjacentTiles(board *Board, rules GameRules) bool {
 		"Duration": elapsed,
 	}).Debug(" < ValidateAdjacentTiles finish")
 	if scoresOver300 > rules.MaxOver300 {
-		return false
+		return invalidResult(rule, positionsOver300, scoresOver300, rules.MaxOver300,
+			"%d groups of adjacent tiles have a score over 300, more than the maximum of %d", scoresOver300, rules.MaxOver300)
 	}
 
-	return true
+	return validResult(rule)
 }
 
 // ValidateTilesNumbers validates if the number of tiles in the board matches the expected tiles for a given game type
-func ValidateTilesNumbers(board *Board, rules GameRules) bool {
+func ValidateTilesNumbers(board *Board, rules GameRules) ValidationResult {
+	const rule = "TilesNumbers"
+	if !board.complete() {
+		return validResult(rule)
+	}
 	log.Debug(" > ValidateTilesNumbers start")
-	return len(board.Tiles) == board.GameType.TilesCount
+	if len(board.Tiles) != board.GameType.TilesCount {
+		return invalidResult(rule, nil, len(board.Tiles), board.GameType.TilesCount,
+			"the board has %d tiles, where %s has %d", len(board.Tiles), board.GameType.Name, board.GameType.TilesCount)
+	}
+	return validResult(rule)
 }
 
 // ValidateHarbors validates whether or not a harbor is linked to a resource tile with the same resource as the harbor
-func ValidateHarbors(board *Board, rules GameRules) bool {
+// On boards with variable harbors, every harbor also has to face the coast, without sharing an intersection with another harbor
+func ValidateHarbors(board *Board, rules GameRules) ValidationResult {
+	const rule = "Harbors"
 	log.Debug(" > ValidateHarbors start")
-	harborsAreValid := true
-	for _,tile := range board.Tiles {
-		if tile.Harbor != *model.HarborNone && tile.Harbor.Resource.Code == tile.Landscape.Resource.Code{
-			harborsAreValid = false
-			log.Warnf("Harbor INVALID: %v (harbor) %v (tile)", tile.Harbor.Resource.Code, tile.Landscape.Resource.Code)
+	invalidHarbors := make([]string, 0)
+	for _, position := range board.Positions() {
+		tile := board.tile(position)
+		if tile == nil {
+			continue
+		}
+		if tile.Harbor != *model.HarborNone && tile.Harbor.Resource.Code == tile.Landscape.Resource.Code {
+			invalidHarbors = append(invalidHarbors, position)
+			log.Debugf("Harbor INVALID: %v (harbor) %v (tile)", tile.Harbor.Resource.Code, tile.Landscape.Resource.Code)
 		}
 	}
 	log.Debug(" < ValidateHarbors finish")
-	return harborsAreValid
+	if len(invalidHarbors) > 0 {
+		return invalidResult(rule, invalidHarbors, len(invalidHarbors), 0,
+			"%d harbors are on a tile that produces the resource they trade", len(invalidHarbors))
+	}
+	if misplaced := misplacedHarbors(board); len(misplaced) > 0 {
+		return invalidResult(rule, misplaced, len(misplaced), 0,
+			"%d harbors do not face the coast, or share an intersection with another harbor", len(misplaced))
+	}
+	return validResult(rule)
 }
 
+const resourceSpreadRule = "ResourceSpread"
+
 // ValidateResourceSpread validates whether resources are spread on the board.
 // There shouldn't be too many of the same resource next to each other.
-func ValidateResourceSpread(board *Board, rules GameRules) bool {
-	adjacentSameValidation := true
+func ValidateResourceSpread(board *Board, rules GameRules) ValidationResult {
 	if rules.AdjacentSame == 0 {
-		log.Infof("Adjacent Tiles Cannot Have The Same Resource is Enabled!")
-		adjacentSameValidation = validateResourcesPerAdjacentTiles(board, rules)
+		log.Debugf("Adjacent Tiles Cannot Have The Same Resource is Enabled!")
+		if result := validateResourcesPerAdjacentTiles(board, rules); !result.Valid {
+			return result
+		}
+	}
+	if result := validateResourcesPerColumn(board, rules); !result.Valid {
+		return result
 	}
-	return adjacentSameValidation && validateResourcesPerColumn(board, rules) && validateResourcesPerRow(board, rules)
+	return validateResourcesPerRow(board, rules)
 }
 
-func validateResourcesPerAdjacentTiles(board *Board, rules GameRules) bool {
+func validateResourcesPerAdjacentTiles(board *Board, rules GameRules) ValidationResult {
 	for _, tileGroup := range board.GameType.AdjacentTileGroups {
-		element0 := board.element(strings.Replace(tileGroup[0], "w", "l", 1))
-		element1 := board.element(strings.Replace(tileGroup[1], "w", "l", 1))
-		element2 := board.element(strings.Replace(tileGroup[2], "w", "l", 1))
-		if element0 == element1 && element1 == element2 {
-			log.Warnf("Found the same tiles: %s, %s, %s", element0, element1, element2)
-			return false
+		tile0 := board.tile(tileGroup[0])
+		tile1 := board.tile(tileGroup[1])
+		tile2 := board.tile(tileGroup[2])
+		if tile0 == nil || tile1 == nil || tile2 == nil {
+			continue
+		}
+		if tile0.Landscape == tile1.Landscape && tile1.Landscape == tile2.Landscape {
+			log.Debugf("Found the same tiles: %v, %v, %v", tile0.Landscape, tile1.Landscape, tile2.Landscape)
+			return invalidResult(resourceSpreadRule, append([]string{}, tileGroup...), len(tileGroup), len(tileGroup)-1,
+				"the adjacent tiles %v are all %s", tileGroup, tile0.Landscape.Name)
 		}
 	}
-	return true
+	return validResult(resourceSpreadRule)
 }
 
 // ValidateResourcesPerColumn validates if there's not too many of the same landscape type
@@ -202,97 +316,234 @@ func validateResourcesPerAdjacentTiles(board *Board, rules GameRules) bool {
 // c0, d0, f0
 // c1, d1, f1
 // c2, d2, f2
-func validateResourcesPerColumn(board *Board, rules GameRules) bool {
+func validateResourcesPerColumn(board *Board, rules GameRules) ValidationResult {
 
 	initialRuneId := int('a')
 	numberOfRows := len(board.Board)
 	halfNumberOfRows := numberOfRows / 2
 	// first, do left arc
-	leftIsValid := validateResourcePerColumnArc(board, rules, initialRuneId)
+	if result := validateResourcePerColumnArc(board, rules, initialRuneId); !result.Valid {
+		return result
+	}
 	// then do right arc
-	rightIsValid := validateResourcePerColumnArc(board, rules, initialRuneId+halfNumberOfRows)
-	return leftIsValid && rightIsValid
+	return validateResourcePerColumnArc(board, rules, initialRuneId+halfNumberOfRows)
 }
 
-func validateResourcePerColumnArc(board *Board, rules GameRules, initialRune int) bool {
+func validateResourcePerColumnArc(board *Board, rules GameRules, initialRune int) ValidationResult {
 	numberOfRows := len(board.Board)
 	// we do c0, b0, a0 -> 5 / 2 = 2 + 1 -> 3
 	numberOfRowsToCheck := (numberOfRows / 2) + 1
 	numberOfColumns := len(board.Board["a"])
 	for i := 0; i < numberOfColumns; i++ {
-		countField := 0
-		countForest := 0
-		countPasture := 0
-		countMountain := 0
-		countHill := 0
+		positions := make([]string, 0, numberOfRowsToCheck)
 		rowRune := rune(initialRune)
 
 		for j := 0; j < numberOfRowsToCheck; j++ {
 			rowIndex := string(rowRune)
-			//rowIndex := strconv.QuoteRune(rowRune)
 			rowRune++
-			tile := board.Board[rowIndex][i]
-			switch tile.Landscape.Code {
-			case model.Brick.Code:
-				countHill++
-			case model.Field.Code:
-				countField++
-			case model.Pasture.Code:
-				countPasture++
-			case model.Mountain.Code:
-				countMountain++
-			case model.Forest.Code:
-				countForest++
-			}
+			positions = append(positions, fmt.Sprintf("%s%d", rowIndex, i))
 		}
 
-		if countMountain > rules.MaxSameLandscapePerColumn ||
-			countForest > rules.MaxSameLandscapePerColumn ||
-			countPasture > rules.MaxSameLandscapePerColumn ||
-			countField > rules.MaxSameLandscapePerColumn ||
-			countHill > rules.MaxSameLandscapePerColumn {
-			log.Warnf("Too many tiles of the same landscape type in a column arc: %v\n", rules.MaxSameLandscapePerColumn)
-			return false
+		landscape, samePositions := mostCommonLandscape(board, positions)
+		if len(samePositions) > rules.MaxSameLandscapePerColumn {
+			log.Debugf("Too many tiles of the same landscape type in a column arc: %v\n", rules.MaxSameLandscapePerColumn)
+			return invalidResult(resourceSpreadRule, samePositions, len(samePositions), rules.MaxSameLandscapePerColumn,
+				"%d %s tiles in the column arc %v, more than the maximum of %d", len(samePositions), landscape, positions, rules.MaxSameLandscapePerColumn)
 		}
 	}
 
-	return true
+	return validResult(resourceSpreadRule)
 }
 
 // validateResourcesPerRow validates if there's not too many of the same landscape type
 // per row. Rows being a, b, c and so on.
-func validateResourcesPerRow(board *Board, rules GameRules) bool {
-
-	for _, column := range board.Board {
-		if len(column) > 0 {
-			countField := 0
-			countForest := 0
-			countPasture := 0
-			countMountain := 0
-			countHill := 0
-			for _, tile := range column {
-				switch tile.Landscape.Code {
-				case model.Brick.Code:
-					countHill++
-				case model.Field.Code:
-					countField++
-				case model.Pasture.Code:
-					countPasture++
-				case model.Mountain.Code:
-					countMountain++
-				case model.Forest.Code:
-					countForest++
-				}
-			}
-			if countMountain > rules.MaxSameLandscapePerRow ||
-				countForest > rules.MaxSameLandscapePerRow ||
-				countPasture > rules.MaxSameLandscapePerRow ||
-				countField > rules.MaxSameLandscapePerRow ||
-				countHill > rules.MaxSameLandscapePerRow {
-				log.Warnf("Too many tiles of the same landscape type in a Row: %v\n", rules.MaxSameLandscapePerRow)
-				return false
+func validateResourcesPerRow(board *Board, rules GameRules) ValidationResult {
+	rows := make([]string, 0, len(board.Board))
+	for row := range board.Board {
+		rows = append(rows, row)
+	}
+	sort.Strings(rows)
+
+	for _, row := range rows {
+		positions := make([]string, 0, len(board.Board[row]))
+		for index := range board.Board[row] {
+			positions = append(positions, fmt.Sprintf("%s%d", row, index))
+		}
+		landscape, samePositions := mostCommonLandscape(board, positions)
+		if len(samePositions) > rules.MaxSameLandscapePerRow {
+			log.Debugf("Too many tiles of the same landscape type in a Row: %v\n", rules.MaxSameLandscapePerRow)
+			return invalidResult(resourceSpreadRule, samePositions, len(samePositions), rules.MaxSameLandscapePerRow,
+				"%d %s tiles in row %s, more than the maximum of %d", len(samePositions), landscape, row, rules.MaxSameLandscapePerRow)
+		}
+	}
+	return validResult(resourceSpreadRule)
+}
+
+// mostCommonLandscape returns the regular landscape that is most common among the positions, and the positions that have it
+func mostCommonLandscape(board *Board, positions []string) (string, []string) {
+	positionsPerLandscape := make(map[string][]string)
+	for _, position := range positions {
+		tile := board.tile(position)
+		if tile == nil {
+			continue
+		}
+		positionsPerLandscape[tile.Landscape.Code] = append(positionsPerLandscape[tile.Landscape.Code], position)
+	}
+
+	landscape := ""
+	mostPositions := make([]string, 0)
+	for _, resource := range regularResources {
+		if len(positionsPerLandscape[resource.Code]) > len(mostPositions) {
+			landscape = model.Landscapes[resource.Code].Name
+			mostPositions = positionsPerLandscape[resource.Code]
+		}
+	}
+	return landscape, mostPositions
+}
+
+// ValidateRedNumbers validates that the red numbers, the 6 and 8 that are rolled most often, are not on neighbouring tiles
+// Only applies when the NoAdjacentRed rule is enabled
+func ValidateRedNumbers(board *Board, rules GameRules) ValidationResult {
+	const rule = "RedNumbers"
+	if !rules.NoAdjacentRed {
+		return validResult(rule)
+	}
+	log.Debug(" > ValidateRedNumbers start")
+	pairs := 0
+	positions := make([]string, 0)
+	for _, pair := range board.GameType.NeighbouringTiles {
+		tileA := board.tile(pair[0])
+		tileB := board.tile(pair[1])
+		if tileA != nil && tileB != nil && isRedNumber(tileA.Number) && isRedNumber(tileB.Number) {
+			log.Debugf("Red numbers on neighbouring tiles: %s (%d) and %s (%d)", pair[0], tileA.Number.Number, pair[1], tileB.Number.Number)
+			pairs++
+			positions = appendMissing(positions, pair[0], pair[1])
+		}
+	}
+	log.Debug(" < ValidateRedNumbers finish")
+	if pairs > 0 {
+		return invalidResult(rule, positions, pairs, 0, "%d pairs of neighbouring tiles both have a red number", pairs)
+	}
+	return validResult(rule)
+}
+
+func isRedNumber(number model.Number) bool {
+	return number.Number == model.Number6.Number || number.Number == model.Number8.Number
+}
+
+// ValidateSameNumbers validates that two tiles with the same number are not neighbours
+// Only applies when the NoAdjacentSameNumber rule is enabled
+func ValidateSameNumbers(board *Board, rules GameRules) ValidationResult {
+	const rule = "SameNumbers"
+	if !rules.NoAdjacentSameNumber {
+		return validResult(rule)
+	}
+	log.Debug(" > ValidateSameNumbers start")
+	pairs := 0
+	positions := make([]string, 0)
+	for _, pair := range board.GameType.NeighbouringTiles {
+		tileA := board.tile(pair[0])
+		tileB := board.tile(pair[1])
+		if tileA != nil && tileB != nil && tileA.Number.Pips() > 0 && tileA.Number.Number == tileB.Number.Number {
+			log.Debugf("Same number on neighbouring tiles: %s and %s (%d)", pair[0], pair[1], tileA.Number.Number)
+			pairs++
+			positions = appendMissing(positions, pair[0], pair[1])
+		}
+	}
+	log.Debug(" < ValidateSameNumbers finish")
+	if pairs > 0 {
+		return invalidResult(rule, positions, pairs, 0, "%d pairs of neighbouring tiles have the same number", pairs)
+	}
+	return validResult(rule)
+}
+
+// ValidateNumbersPerResource validates that a resource does not get the same number on more than one tile,
+// such as both 6s on Fields, so a single roll does not favour one resource
+// Only applies when the NoSameNumberPerResource rule is enabled
+func ValidateNumbersPerResource(board *Board, rules GameRules) ValidationResult {
+	const rule = "NumbersPerResource"
+	if !rules.NoSameNumberPerResource {
+		return validResult(rule)
+	}
+	log.Debug(" > ValidateNumbersPerResource start")
+	positionsPerNumber := make(map[string][]string)
+	keys := make([]string, 0)
+	for _, position := range board.Positions() {
+		tile := board.tile(position)
+		if tile == nil || tile.Number.Pips() == 0 {
+			continue
+		}
+		key := fmt.Sprintf("the %d on %s", tile.Number.Number, tile.Landscape.Resource.Name)
+		if _, ok := positionsPerNumber[key]; !ok {
+			keys = append(keys, key)
+		}
+		positionsPerNumber[key] = append(positionsPerNumber[key], position)
+	}
+	log.Debug(" < ValidateNumbersPerResource finish")
+
+	for _, key := range keys {
+		if positions := positionsPerNumber[key]; len(positions) > 1 {
+			log.Debugf("Found %s on more than one tile", key)
+			return invalidResult(rule, positions, len(positions), 1, "%s on %d tiles", key, len(positions))
+		}
+	}
+	return validResult(rule)
+}
+
+// ValidateFirstSeatAdvantage validates that the first seat does not get a much better start than the others,
+// by a greedy draft of the opening settlements for the Players of the rules
+// Only applies when the MaxFirstSeatAdvantage rule is set, and to a board with every tile placed
+func ValidateFirstSeatAdvantage(board *Board, rules GameRules) ValidationResult {
+	const rule = "FirstSeatAdvantage"
+	if rules.MaxFirstSeatAdvantage <= 0 || !board.complete() {
+		return validResult(rule)
+	}
+	log.Debug(" > ValidateFirstSeatAdvantage start")
+	draft, err := DraftPlacements(board, DraftPlayers(rules), PlacementGreedy)
+	log.Debug(" < ValidateFirstSeatAdvantage finish")
+	if err != nil || draft.FirstSeatAdvantage <= rules.MaxFirstSeatAdvantage {
+		return validResult(rule)
+	}
+
+	positions := append([]string{}, draft.Seats[0].Tiles...)
+	return invalidResult(rule, positions, draft.FirstSeatAdvantage, rules.MaxFirstSeatAdvantage,
+		"the first seat has %d pips more than the average of the other seats", draft.FirstSeatAdvantage)
+}
+
+// ValidateSeatGap validates that every seat can get a comparable start, by a greedy draft of the opening settlements
+// for the Players of the rules, in which the best seat may not get more than MaxSeatGap pips above the worst seat
+// Only applies when the MaxSeatGap rule is set, and to a board with every tile placed
+func ValidateSeatGap(board *Board, rules GameRules) ValidationResult {
+	const rule = "SeatGap"
+	if rules.MaxSeatGap <= 0 || !board.complete() {
+		return validResult(rule)
+	}
+	log.Debug(" > ValidateSeatGap start")
+	draft, err := DraftPlacements(board, DraftPlayers(rules), PlacementGreedy)
+	log.Debug(" < ValidateSeatGap finish")
+	if err != nil || draft.SeatGap <= rules.MaxSeatGap {
+		return validResult(rule)
+	}
+
+	best, worst := draft.BestSeat(), draft.WorstSeat()
+	positions := appendMissing(append([]string{}, best.Tiles...), worst.Tiles...)
+	return invalidResult(rule, positions, draft.SeatGap, rules.MaxSeatGap,
+		"seat %d has %d pips more than seat %d of %d players", best.Seat, draft.SeatGap, worst.Seat, draft.Players)
+}
+
+// appendMissing appends the positions that are not in the list yet
+func appendMissing(list []string, positions ...string) []string {
+	for _, position := range positions {
+		found := false
+		for _, existing := range list {
+			if existing == position {
+				found = true
+				break
 			}
 		}
+		if !found {
+			list = append(list, position)
+		}
 	}
-	return true
+	return list
 }
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// a normal game with the red numbers on a1 (8), b0 (6), c2 (8) and d3 (6), none of them neighbours
+const validationsTestCode = "2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0"
+
+func inflateValidationsTestBoard(t *testing.T) *Board {
+	gameType := NormalGame
+	board, err := InflateNormalGameFromCode(validationsTestCode, &gameType)
+	assert.NoError(t, err)
+	return &board
+}
+
+func TestValidateRedNumbersApart(t *testing.T) {
+	board := inflateValidationsTestBoard(t)
+	rules := DefaultGameRulesNormal
+	rules.NoAdjacentRed = true
+	assert.True(t, ValidateRedNumbers(board, rules).Valid)
+}
+
+func TestValidateRedNumbersNeighbours(t *testing.T) {
+	board := inflateValidationsTestBoard(t)
+	// b1 is a neighbour of the 8 on a1
+	board.tile("b1").Number = *model.Number6
+	rules := DefaultGameRulesNormal
+
+	rules.NoAdjacentRed = true
+	assert.False(t, ValidateRedNumbers(board, rules).Valid)
+
+	rules.NoAdjacentRed = false
+	assert.True(t, ValidateRedNumbers(board, rules).Valid)
+}
+
+func TestNeighbouringTilesNormal(t *testing.T) {
+	pairs := NormalGame.NeighbouringTiles
+	// every tile has up to six neighbours, every shared edge is counted once
+	assert.Equal(t, 42, len(pairs))
+	assert.Contains(t, pairs, [2]string{"a1", "b1"})
+	assert.Contains(t, pairs, [2]string{"c2", "d1"})
+	assert.NotContains(t, pairs, [2]string{"a1", "b0"})
+}
+
+func TestValidateSameNumbers(t *testing.T) {
+	board := inflateValidationsTestBoard(t)
+	rules := DefaultGameRulesNormal
+	rules.NoAdjacentSameNumber = true
+	// the 10s on b3 and c4 are neighbours, as are the 5s on c1 and d0
+	assert.False(t, ValidateSameNumbers(board, rules).Valid)
+
+	board.tile("c4").Number = *model.Number4
+	board.tile("e1").Number = *model.Number10
+	assert.False(t, ValidateSameNumbers(board, rules).Valid)
+
+	board.tile("a0").Number = *model.Number5
+	board.tile("d0").Number = *model.Number12
+	assert.True(t, ValidateSameNumbers(board, rules).Valid)
+
+	rules.NoAdjacentSameNumber = false
+	board.tile("c3").Number = *model.Number4
+	assert.True(t, ValidateSameNumbers(board, rules).Valid)
+}
+
+func TestValidateNumbersPerResource(t *testing.T) {
+	board := inflateValidationsTestBoard(t)
+	rules := DefaultGameRulesNormal
+	rules.NoSameNumberPerResource = true
+	// both 10s are on Forests, and both 4s on Hills
+	assert.False(t, ValidateNumbersPerResource(board, rules).Valid)
+
+	board.tile("c4").Number = *model.Number4
+	board.tile("e1").Number = *model.Number10
+	assert.True(t, ValidateNumbersPerResource(board, rules).Valid)
+
+	rules.NoSameNumberPerResource = false
+	board.tile("c4").Number = *model.Number10
+	assert.True(t, ValidateNumbersPerResource(board, rules).Valid)
+}
+
+func TestValidateReport(t *testing.T) {
+	board := inflateValidationsTestBoard(t)
+	rules := DefaultGameRulesNormal
+	rules.NoAdjacentSameNumber = true
+
+	report := board.Validate(rules)
+	assert.False(t, report.Valid)
+	assert.Equal(t, len(Validations), len(report.Results))
+
+	var sameNumbers *ValidationResult
+	for _, result := range report.Failures() {
+		if result.Rule == "SameNumbers" {
+			result := result
+			sameNumbers = &result
+		}
+	}
+	if assert.NotNil(t, sameNumbers) {
+		assert.Equal(t, []string{"b3", "c1", "c4", "d0"}, sameNumbers.Positions)
+		assert.Equal(t, 2, sameNumbers.Value)
+		assert.Equal(t, 0, sameNumbers.Threshold)
+		assert.NotEmpty(t, sameNumbers.Message)
+	}
+	assert.Equal(t, report.Valid, board.IsValid(rules, NormalGame))
+}
+
+func TestValidatePartialBoard(t *testing.T) {
+	board := inflateValidationsTestBoard(t)
+	rules := DefaultGameRulesNormal
+	rules.MinimumScore = 1000
+	rules.MinimumResourceScore = 1000
+	assert.False(t, ValidateAdjacentTiles(board, rules).Valid)
+	assert.False(t, ValidateResourceScores(board, rules).Valid)
+
+	// the minimums can not be judged before every tile is placed
+	for _, column := range board.Board {
+		for row := range column {
+			column[row] = nil
+		}
+	}
+	board.Tiles = nil
+	assert.True(t, board.Validate(rules).Valid)
+}
+
+func TestValidationDistance(t *testing.T) {
+	assert.Equal(t, 0, validResult("Valid").Distance())
+	assert.Equal(t, 20, invalidResult("Above", nil, 380, 360, "above").Distance())
+	assert.Equal(t, 15, invalidResult("Below", nil, 150, 165, "below").Distance())
+	assert.Equal(t, 1, invalidResult("At", nil, 2, 2, "at").Distance())
+
+	report := ValidationReport{Results: []ValidationResult{
+		validResult("Valid"),
+		invalidResult("Above", nil, 380, 360, "above"),
+		invalidResult("Pairs", nil, 2, 0, "pairs"),
+	}}
+	assert.Equal(t, 22, report.Distance())
+}
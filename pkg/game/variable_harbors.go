@@ -0,0 +1,238 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/kennygrant/sanitize"
+)
+
+const (
+	// harborDirectionSeparator separates the tiles of a v1 game code from the directions of its harbors
+	harborDirectionSeparator = "~"
+	// harborDrawAttempts the number of times the coastal edges are shuffled to find room for all harbors
+	harborDrawAttempts = 100
+)
+
+// HarborDirections the coastal edge each harbor faces, for boards with variable harbors, by the position of the land tile it is on
+// The direction is the index in the model.HexDirections of the neighbour on the other side of the edge, which is Sea or off the board
+type HarborDirections map[string]int
+
+// harborSlot a coastal edge of a land tile, where a harbor can be
+type harborSlot struct {
+	position  string
+	direction int
+	vertices  [2]model.HexVertex
+}
+
+// DrawHarborDirections draws a coastal edge for each of the harbors of the game type, instead of the fixed HarborLayout
+// No two harbors are on the same tile, and no intersection touches more than one harbor
+// The positions with a locked harbor always get one
+// Returns false when there is no room for all harbors, after shuffling the coastal edges a number of times
+func DrawHarborDirections(gameType GameType, locks Locks, random *rand.Rand) (HarborDirections, bool) {
+	slots := coastalSlots(gameType)
+	required := make([]string, 0)
+	for _, position := range codePositions(gameType) {
+		if locks[position].Harbor != "" {
+			required = append(required, position)
+		}
+	}
+
+	for attempt := 0; attempt < harborDrawAttempts; attempt++ {
+		random.Shuffle(len(slots), func(i, j int) {
+			slots[i], slots[j] = slots[j], slots[i]
+		})
+		directions := HarborDirections{}
+		usedVertices := make(map[model.HexVertex]bool)
+		place := func(slot harborSlot) bool {
+			if _, taken := directions[slot.position]; taken || usedVertices[slot.vertices[0]] || usedVertices[slot.vertices[1]] {
+				return false
+			}
+			directions[slot.position] = slot.direction
+			usedVertices[slot.vertices[0]] = true
+			usedVertices[slot.vertices[1]] = true
+			return true
+		}
+
+		for _, position := range required {
+			for _, slot := range slots {
+				if slot.position == position && place(slot) {
+					break
+				}
+			}
+		}
+		if len(directions) < len(required) {
+			continue
+		}
+		for _, slot := range slots {
+			if len(directions) == gameType.HarborCount {
+				break
+			}
+			place(slot)
+		}
+		if len(directions) == gameType.HarborCount {
+			return directions, true
+		}
+	}
+	return nil, false
+}
+
+// WithHarborDirections returns the game type with the positions of the harbor directions as its HarborLayout,
+// so the harbors are generated on those positions
+func WithHarborDirections(gameType GameType, directions HarborDirections) GameType {
+	layout := make([]string, 0, len(directions))
+	for position := range directions {
+		layout = append(layout, position)
+	}
+	sort.Strings(layout)
+	gameType.HarborLayout = layout
+	return gameType
+}
+
+// coastalSlots returns every edge between a land tile and Sea or the edge of the board, ordered by position and direction
+func coastalSlots(gameType GameType) []harborSlot {
+	land := make(map[model.Hex]bool)
+	sea := make(map[string]bool)
+	for _, position := range gameType.SeaLayout {
+		sea[position] = true
+	}
+	for position, hex := range gameType.Hexes {
+		if !sea[position] {
+			land[hex] = true
+		}
+	}
+
+	slots := make([]harborSlot, 0)
+	for _, position := range codePositions(gameType) {
+		hex, ok := gameType.Hexes[position]
+		if !ok || sea[position] {
+			continue
+		}
+		for direction, neighbour := range hex.Neighbours() {
+			if land[neighbour] {
+				continue
+			}
+			slots = append(slots, harborSlot{
+				position:  position,
+				direction: direction,
+				vertices:  model.NewHexEdge(hex, neighbour).Vertices(),
+			})
+		}
+	}
+	return slots
+}
+
+// GenerateVariableHarborGameCode generates a v1 game code like GenerateLockedGameCode, with the harbors on drawn coastal edges
+// instead of on the HarborLayout, followed by the directions of the harbors
+// Returns false when the harbors do not fit on the coast
+func GenerateVariableHarborGameCode(gameType GameType, locks Locks, random *rand.Rand) (string, bool) {
+	directions, ok := DrawHarborDirections(gameType, locks, random)
+	if !ok {
+		return "", false
+	}
+	code := GenerateLockedGameCode(WithHarborDirections(gameType, directions), locks, random)
+	return code + harborDirectionSeparator + directions.code(codePositions(gameType)), true
+}
+
+// code the directions of the harbors as they are appended to a game code, one digit per harbor,
+// in the order of the positions in the game code
+func (directions HarborDirections) code(positions []string) string {
+	var sb strings.Builder
+	for _, position := range positions {
+		if direction, ok := directions[position]; ok {
+			sb.WriteString(fmt.Sprintf("%d", direction))
+		}
+	}
+	return sb.String()
+}
+
+// splitHarborDirections splits a v1 game code into the code of its tiles and the directions of its harbors, which can be empty
+func splitHarborDirections(code string) (string, string) {
+	tiles, directions, _ := strings.Cut(code, harborDirectionSeparator)
+	return tiles, directions
+}
+
+// applyHarborDirections sets the directions of the harbors of an inflated board, one digit for every tile with a harbor
+// Every harbor has to face the Sea or the edge of the board
+func (board *Board) applyHarborDirections(directionsCode string) error {
+	if directionsCode == "" {
+		return nil
+	}
+	positions := make([]string, 0)
+	for _, position := range board.Positions() {
+		if harbor := board.tile(position).Harbor; harbor.Name != "" && harbor != *model.HarborNone {
+			positions = append(positions, position)
+		}
+	}
+	if len(directionsCode) != len(positions) {
+		return fmt.Errorf("Inflation error: the code has %d harbor directions for %d harbors", len(directionsCode), len(positions))
+	}
+
+	land := board.landHexes()
+	directions := make(HarborDirections, len(positions))
+	for i, position := range positions {
+		direction := int(directionsCode[i] - '0')
+		if direction < 0 || direction >= len(model.HexDirections) {
+			return fmt.Errorf("Inflation error: %s is not a valid code for a harbor direction", sanitize.Name(directionsCode[i:i+1]))
+		}
+		hex, ok := board.GameType.Hexes[position]
+		if !ok {
+			return fmt.Errorf("Inflation error: position %s is not on the board of %s", position, board.GameType.Name)
+		}
+		if _, isLand := land[hex.Neighbour(direction)]; isLand {
+			return fmt.Errorf("Inflation error: the harbor on %s does not face the coast", position)
+		}
+		directions[position] = direction
+	}
+	board.HarborDirections = directions
+	return nil
+}
+
+// misplacedHarbors returns the positions of the harbors with a direction that do not face the coast,
+// or that share an intersection with another harbor
+func misplacedHarbors(board *Board) []string {
+	if len(board.HarborDirections) == 0 {
+		return nil
+	}
+	land := board.landHexes()
+	harborsAtVertex := make(map[model.HexVertex]int)
+	edges := make(map[string]model.HexEdge)
+	offCoast := make(map[string]bool)
+	for position, direction := range board.HarborDirections {
+		hex, ok := board.GameType.Hexes[position]
+		if !ok || direction < 0 || direction >= len(model.HexDirections) {
+			offCoast[position] = true
+			continue
+		}
+		if _, isLand := land[hex.Neighbour(direction)]; isLand {
+			offCoast[position] = true
+			continue
+		}
+		edges[position] = model.NewHexEdge(hex, hex.Neighbour(direction))
+		for _, vertex := range edges[position].Vertices() {
+			harborsAtVertex[vertex]++
+		}
+	}
+
+	misplaced := make([]string, 0)
+	for _, position := range board.Positions() {
+		if offCoast[position] {
+			misplaced = append(misplaced, position)
+			continue
+		}
+		edge, ok := edges[position]
+		if !ok {
+			continue
+		}
+		for _, vertex := range edge.Vertices() {
+			if harborsAtVertex[vertex] > 1 {
+				misplaced = append(misplaced, position)
+				break
+			}
+		}
+	}
+	return misplaced
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawHarborDirections(t *testing.T) {
+	for _, key := range DefinedGames.Keys() {
+		gameType := DefinedGames[key].GameType
+		random := rand.New(rand.NewSource(1))
+		for i := 0; i < 20; i++ {
+			code, ok := GenerateVariableHarborGameCode(gameType, nil, random)
+			if !assert.True(t, ok, key) {
+				break
+			}
+			board, err := DefinedGames[key].Inflate(code)
+			if !assert.NoError(t, err, key) {
+				break
+			}
+			assert.Equal(t, gameType.HarborCount, len(board.HarborDirections), key)
+			assert.Equal(t, gameType.HarborCount, len(board.HarborEdges()), key)
+			assert.Empty(t, misplacedHarbors(&board), key)
+			assert.Equal(t, code, board.GetGameCode(false), key)
+		}
+	}
+}
+
+func TestDrawHarborDirectionsWithLockedHarbor(t *testing.T) {
+	locks, err := ParseLocks("c2:harbor=0,c0:harbor=1", NormalGame)
+	assert.Error(t, err, "c2 is not a harbor slot")
+	locks, err = ParseLocks("c0:harbor=1", NormalGame)
+	assert.NoError(t, err)
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		code, ok := GenerateVariableHarborGameCode(NormalGame, locks, random)
+		assert.True(t, ok)
+		board, err := InflateGameFromCode(code)
+		if assert.NoError(t, err) {
+			assert.Contains(t, board.HarborDirections, "c0")
+			assert.Equal(t, "1", board.Board["c"][0].Harbor.Code)
+		}
+	}
+}
+
+func TestVariableHarborGameCodeV2(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		code, ok := GenerateVariableHarborGameCode(LargeGame, nil, random)
+		assert.True(t, ok)
+		v2, err := ConvertGameCode(code, CodeFormatV2)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(v2, "L2-"), v2)
+		assert.Equal(t, len("L2-")+LargeGame.TilesCount*2+LargeGame.HarborCount+checksumLength, len(v2))
+
+		v1, err := ConvertGameCode(v2, CodeFormatV1)
+		assert.NoError(t, err)
+		assert.Equal(t, code, v1)
+	}
+}
+
+func TestFixedHarborGameCodeHasNoDirections(t *testing.T) {
+	board, err := InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	assert.Empty(t, board.HarborDirections)
+	assert.Equal(t, normalGameCode, board.GetGameCode(false))
+}
+
+func TestInflateInvalidHarborDirections(t *testing.T) {
+	// the normal game code has nine harbors
+	_, err := InflateGameFromCode(normalGameCode + harborDirectionSeparator + "2222")
+	assert.Error(t, err)
+	_, err = InflateGameFromCode(normalGameCode + harborDirectionSeparator + "222222229")
+	assert.Error(t, err)
+	// the harbor on a0 can not face b1, the tile to its lower right
+	_, err = InflateGameFromCode(normalGameCode + harborDirectionSeparator + "022222222")
+	assert.Error(t, err)
+}
+
+func TestValidateHarborsTooClose(t *testing.T) {
+	board, err := InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	for _, tile := range board.Tiles {
+		tile.Harbor = *model.HarborNone
+	}
+	board.Board["a"][0].Harbor = *model.HarborAll
+	board.Board["a"][1].Harbor = *model.HarborAll
+	board.HarborDirections = HarborDirections{"a0": 2, "a1": 4}
+	assert.True(t, ValidateHarbors(&board, DefaultGameRulesNormal).Valid)
+
+	// the edges to the lower left of a0 and the upper left of a1 meet in an intersection
+	board.HarborDirections["a0"] = 4
+	board.HarborDirections["a1"] = 3
+	result := ValidateHarbors(&board, DefaultGameRulesNormal)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []string{"a0", "a1"}, result.Positions)
+}
@@ -0,0 +1,233 @@
+package mapgen
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// placementsPerTile limits how many tiles a single backtracking attempt may place, per tile on the board,
+// a search that gets stuck deep in the board is better off starting over with a different order
+const placementsPerTile = 25
+
+// backtracker fills a board one position at a time, and undoes a placement as soon as it violates one of the Validations
+type backtracker struct {
+	board      *game.Board
+	rules      game.GameRules
+	random     *rand.Rand
+	positions  []string
+	landscapes []model.Landscape
+	numbers    []model.Number
+	placements int
+	budget     int
+}
+
+// candidate a landscape and number that can be placed on a position
+type candidate struct {
+	landscape int
+	number    int
+}
+
+// GenerateBoardByBacktracking generates a board that satisfies the GameRules by placing the tiles one by one,
+// and backtracking as soon as a placed tile violates a rule, instead of generating whole boards until one is valid
+// Each attempt starts over with a new random order, the number of attempts is limited by the Generations of the rules
+func GenerateBoardByBacktracking(gameType game.GameType, rules game.GameRules, random *rand.Rand) (game.Board, int, error) {
+	start := time.Now()
+	for attempt := 1; attempt <= rules.Generations; attempt++ {
+		board, ok := BacktrackingGenerationAttempt(gameType, rules, random)
+		if ok {
+			debugLogDuration(start, " < GenerateBoardByBacktracking finish")
+			return board, attempt, nil
+		}
+		log.Debugf("Backtracking attempt %d did not find a valid board", attempt)
+	}
+	return game.Board{}, rules.Generations, ErrGenerationLimit
+}
+
+// BacktrackingGenerationAttempt attempts to generate a valid board for the game type by backtracking
+// The harbors are distributed first, then the tiles are placed column by column,
+// the attempt gives up when it has placed too many tiles without completing the board
+func BacktrackingGenerationAttempt(gameType game.GameType, rules game.GameRules, random *rand.Rand) (game.Board, bool) {
+	boardMap, positions := emptyBoard(gameType)
+	harborMap := distributeHarbors(gameType, random)
+	for position, harbor := range harborMap {
+		// the other tiles get their harbor when they are placed
+		if tile := tileAt(boardMap, position); tile != nil {
+			tile.Harbor = *harbor
+		}
+	}
+
+	landscapes := make([]model.Landscape, 0, len(positions))
+	for _, tile := range generateTiles(gameType) {
+		landscapes = append(landscapes, tile.Landscape)
+	}
+	numbers := make([]model.Number, 0, len(gameType.NumberSet))
+	for _, number := range gameType.NumberSet {
+		numbers = append(numbers, *number)
+	}
+
+	b := backtracker{
+		board: &game.Board{
+			Tiles:    seaTiles(boardMap),
+			Board:    boardMap,
+			GameType: gameType,
+			Harbors:  harborMap,
+		},
+		rules:      rules,
+		random:     random,
+		positions:  positions,
+		landscapes: landscapes,
+		numbers:    numbers,
+		budget:     placementsPerTile * len(positions),
+	}
+	if !b.place(0) {
+		return game.Board{}, false
+	}
+	return *b.board, b.board.IsValid(rules, gameType)
+}
+
+// emptyBoard creates a board with only the Sea tiles, and returns the positions of the other tiles ordered by column and row
+func emptyBoard(gameType game.GameType) (map[string][]*model.Tile, []string) {
+	seaPositions := make(map[string]bool)
+	for _, position := range gameType.SeaLayout {
+		seaPositions[position] = true
+	}
+
+	columns := make([]string, 0, len(gameType.BoardLayout))
+	for column := range gameType.BoardLayout {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	boardMap := make(map[string][]*model.Tile)
+	positions := make([]string, 0, gameType.TilesCount)
+	for _, column := range columns {
+		boardMap[column] = make([]*model.Tile, gameType.BoardLayout[column])
+		for row := range boardMap[column] {
+			position := fmt.Sprintf("%s%d", column, row)
+			if seaPositions[position] {
+				boardMap[column][row] = &model.Tile{
+					Landscape: *model.Sea,
+					Number:    *model.NumberEmpty,
+					Harbor:    *model.HarborNone,
+				}
+				continue
+			}
+			positions = append(positions, position)
+		}
+	}
+	return boardMap, positions
+}
+
+// place fills the position at the index and all that follow it, it returns false if there is no valid way to do so
+func (b *backtracker) place(index int) bool {
+	if index == len(b.positions) {
+		return true
+	}
+	position := b.positions[index]
+	harbor := b.harbor(position)
+	for _, next := range b.candidates() {
+		if b.placements >= b.budget {
+			return false
+		}
+		b.placements++
+
+		landscape := b.landscapes[next.landscape]
+		number := *model.NumberEmpty
+		if next.number >= 0 {
+			number = b.numbers[next.number]
+		}
+		tile := &model.Tile{Landscape: landscape, Number: number, Harbor: harbor}
+		b.setTile(position, tile)
+		b.board.Tiles = append(b.board.Tiles, tile)
+		b.landscapes = remove(b.landscapes, next.landscape)
+		if next.number >= 0 {
+			b.numbers = remove(b.numbers, next.number)
+		}
+
+		if satisfies(b.board, b.rules) && b.place(index+1) {
+			return true
+		}
+
+		b.setTile(position, nil)
+		b.board.Tiles = b.board.Tiles[:len(b.board.Tiles)-1]
+		b.landscapes = restore(b.landscapes, next.landscape, landscape)
+		if next.number >= 0 {
+			b.numbers = restore(b.numbers, next.number, number)
+		}
+	}
+	return false
+}
+
+// candidates returns every distinct combination of the remaining landscapes and numbers in a random order
+// A Desert does not get a number, which is marked by a number index of -1
+func (b *backtracker) candidates() []candidate {
+	landscapes := distinct(len(b.landscapes), func(i int) string { return b.landscapes[i].Name })
+	numbers := distinct(len(b.numbers), func(i int) string { return b.numbers[i].Code })
+
+	candidates := make([]candidate, 0, len(landscapes)*len(numbers))
+	for _, landscape := range landscapes {
+		if b.landscapes[landscape] == *model.Desert {
+			candidates = append(candidates, candidate{landscape: landscape, number: -1})
+			continue
+		}
+		for _, number := range numbers {
+			candidates = append(candidates, candidate{landscape: landscape, number: number})
+		}
+	}
+	b.random.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	return candidates
+}
+
+func (b *backtracker) harbor(position string) model.Harbor {
+	if harbor, ok := b.board.Harbors[position]; ok {
+		return *harbor
+	}
+	return *model.HarborNone
+}
+
+func (b *backtracker) setTile(position string, tile *model.Tile) {
+	row, _ := strconv.Atoi(position[1:])
+	b.board.Board[position[0:1]][row] = tile
+}
+
+func tileAt(tiles map[string][]*model.Tile, position string) *model.Tile {
+	row, _ := strconv.Atoi(position[1:])
+	return tiles[position[0:1]][row]
+}
+
+// distinct returns the index of the first of each distinct key, in order
+func distinct(length int, key func(int) string) []int {
+	seen := make(map[string]bool)
+	indexes := make([]int, 0, length)
+	for i := 0; i < length; i++ {
+		if !seen[key(i)] {
+			seen[key(i)] = true
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// remove takes the element at the index out of the list, by moving the last element in its place
+func remove[T any](list []T, index int) []T {
+	last := len(list) - 1
+	list[index], list[last] = list[last], list[index]
+	return list[:last]
+}
+
+// restore puts an element back that was taken out by remove
+func restore[T any](list []T, index int, element T) []T {
+	list = append(list, element)
+	last := len(list) - 1
+	list[index], list[last] = list[last], list[index]
+	return list
+}
@@ -0,0 +1,129 @@
+package mapgen
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBacktrackingGeneratesValidBoards(t *testing.T) {
+	for key, definedGame := range game.DefinedGames {
+		board, attempts, err := GenerateBoardByBacktracking(definedGame.GameType, definedGame.Rules, rand.New(rand.NewSource(1)))
+		if assert.NoError(t, err, key) {
+			assert.Positive(t, attempts, key)
+			assert.True(t, board.Validate(definedGame.Rules).Valid, key)
+			assert.Equal(t, definedGame.GameType.TilesCount, len(board.Tiles), key)
+			assert.Equal(t, definedGame.GameType.TilesCount*3, len(board.GetGameCode(false)), key)
+		}
+	}
+}
+
+func TestBacktrackingPlacesAllTiles(t *testing.T) {
+	board, _, err := GenerateBoardByBacktracking(game.NormalGame, game.DefaultGameRulesNormal, rand.New(rand.NewSource(7)))
+	assert.NoError(t, err)
+
+	landscapes := make(map[string]int)
+	numbers := make(map[string]int)
+	for _, tile := range board.Tiles {
+		landscapes[tile.Landscape.Name]++
+		numbers[tile.Number.Code]++
+	}
+	expectedNumbers := make(map[string]int)
+	for _, number := range game.NormalGame.NumberSet {
+		expectedNumbers[number.Code]++
+	}
+	expectedNumbers["z"] = game.NormalGame.DesertCount
+
+	assert.Equal(t, game.NormalGame.DesertCount, landscapes["Desert"])
+	assert.Equal(t, game.NormalGame.ForestCount, landscapes["Forest"])
+	assert.Equal(t, game.NormalGame.MountainCount, landscapes["Mountain"])
+	assert.Equal(t, expectedNumbers, numbers)
+	assert.Equal(t, game.NormalGame.HarborCount, len(board.Harbors))
+}
+
+func TestBacktrackingWithStrictRules(t *testing.T) {
+	rules := game.DefaultGameRulesNormal
+	rules.NoAdjacentRed = true
+	rules.NoAdjacentSameNumber = true
+	rules.NoSameNumberPerResource = true
+
+	board, _, err := GenerateBoardByBacktracking(game.NormalGame, rules, rand.New(rand.NewSource(3)))
+	if assert.NoError(t, err) {
+		assert.True(t, board.Validate(rules).Valid)
+	}
+}
+
+func TestBacktrackingSameSeedGeneratesSameMap(t *testing.T) {
+	options := GenerationOptions{Seed: 1234, Strategy: StrategyBacktracking}
+	mapA, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesLarge, options, model.RequestInfo{})
+	assert.NoError(t, err)
+	mapB, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesLarge, options, model.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, game.LargeGame.Name, mapA.GameType)
+	assert.Equal(t, mapA.GameCode, mapB.GameCode)
+}
+
+func TestParseStrategy(t *testing.T) {
+	strategy, ok := ParseStrategy("")
+	assert.True(t, ok)
+	assert.Equal(t, StrategyRandom, strategy)
+
+	strategy, ok = ParseStrategy("backtracking")
+	assert.True(t, ok)
+	assert.Equal(t, StrategyBacktracking, strategy)
+
+	_, ok = ParseStrategy("guess")
+	assert.False(t, ok)
+}
+
+func benchmarkStrategy(b *testing.B, rules game.GameRules, strategy Strategy) {
+	level := log.GetLevel()
+	log.SetLevel(log.ErrorLevel)
+	defer log.SetLevel(level)
+
+	failures := 0
+	for i := 0; i < b.N; i++ {
+		options := GenerationOptions{Seed: int64(i + 1), Strategy: strategy}
+		if _, err := ProcessMapGenerationRequest(context.Background(), rules, options, model.RequestInfo{}); err != nil {
+			failures++
+		}
+	}
+	b.ReportMetric(float64(failures)/float64(b.N), "failures/op")
+}
+
+func BenchmarkRandomNormal(b *testing.B) {
+	benchmarkStrategy(b, game.DefaultGameRulesNormal, StrategyRandom)
+}
+
+func BenchmarkBacktrackingNormal(b *testing.B) {
+	benchmarkStrategy(b, game.DefaultGameRulesNormal, StrategyBacktracking)
+}
+
+func BenchmarkRandomNormalStrict(b *testing.B) {
+	rules := game.DefaultGameRulesNormal
+	rules.NoAdjacentRed = true
+	rules.NoAdjacentSameNumber = true
+	rules.NoSameNumberPerResource = true
+	benchmarkStrategy(b, rules, StrategyRandom)
+}
+
+func BenchmarkBacktrackingNormalStrict(b *testing.B) {
+	rules := game.DefaultGameRulesNormal
+	rules.NoAdjacentRed = true
+	rules.NoAdjacentSameNumber = true
+	rules.NoSameNumberPerResource = true
+	benchmarkStrategy(b, rules, StrategyBacktracking)
+}
+
+func BenchmarkRandomLarge(b *testing.B) {
+	benchmarkStrategy(b, game.DefaultGameRulesLarge, StrategyRandom)
+}
+
+func BenchmarkBacktrackingLarge(b *testing.B) {
+	benchmarkStrategy(b, game.DefaultGameRulesLarge, StrategyBacktracking)
+}
@@ -0,0 +1,77 @@
+package mapgen
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/joostvdg/cmg/pkg/game"
+)
+
+// maxRandomSeed keeps generated seeds within the range a JSON number can represent exactly,
+// so clients (such as JavaScript) can send the seed back without losing precision
+const maxRandomSeed = 1 << 53
+
+// Strategy how a map that satisfies the GameRules is searched for
+type Strategy string
+
+const (
+	// StrategyRandom generates whole random boards, until one satisfies the GameRules
+	StrategyRandom Strategy = "random"
+	// StrategyBacktracking places the tiles one by one, and undoes a placement as soon as it violates the GameRules
+	StrategyBacktracking Strategy = "backtracking"
+)
+
+// Strategies the strategies that can be used to generate a map
+var Strategies = []Strategy{StrategyRandom, StrategyBacktracking}
+
+// GenerationOptions the options for generating a map, as opposed to the GameRules the generated map has to satisfy
+// A Seed of 0 means a random seed is chosen, the seed that was used is reported back so the map can be reproduced
+// An empty Strategy means the StrategyRandom, and a Timeout of 0 means the generation only stops at the Generations limit
+// With BestEffort the map that is closest to satisfying the GameRules is returned, when none of the maps satisfies them
+// With Optimization the valid map is optimized for a more balanced board
+// With Locks only the tiles, numbers and harbors that are not locked are generated, whatever the Strategy,
+// by generating game codes around the locked tiles
+// A nil NumberPlacement means the RandomNumbers, any other placement places the numbers again on every generated board,
+// which is then validated without the MinimumScore of the adjacent tiles
+type GenerationOptions struct {
+	Seed         int64
+	Strategy     Strategy
+	Timeout      time.Duration
+	BestEffort   bool
+	Optimization *OptimizationOptions
+	Locks        game.Locks
+	Numbers      NumberPlacement
+}
+
+// optimizationOptions returns the options of the Optimization, with the Locks the optimizer has to keep in place
+// When the numbers of the board are placed by a NumberPlacement other than the RandomNumbers, the numbers are locked as well
+func (options GenerationOptions) optimizationOptions(board *game.Board) OptimizationOptions {
+	optimization := *options.Optimization
+	optimization.Locks = options.Locks
+	if numbersAgain(options.Numbers) {
+		optimization.Locks = numberLocks(board, options.Locks)
+	}
+	return optimization
+}
+
+// ParseStrategy returns the Strategy with the given name, an empty name is the StrategyRandom
+func ParseStrategy(name string) (Strategy, bool) {
+	if name == "" {
+		return StrategyRandom, true
+	}
+	for _, strategy := range Strategies {
+		if string(strategy) == name {
+			return strategy, true
+		}
+	}
+	return "", false
+}
+
+// NewRandom creates the random source for a generation request, and returns the seed it is based on
+// The same seed with the same GameRules results in the same sequence of attempts, and thus in the same map
+func NewRandom(seed int64) (*rand.Rand, int64) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()%maxRandomSeed + 1
+	}
+	return rand.New(rand.NewSource(seed)), seed
+}
@@ -0,0 +1,40 @@
+package mapgen
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// LockedGenerationAttempt generates a board for the game type around the locked tiles
+// Only the landscapes, numbers and harbors that are not locked are shuffled into a game code, which is then inflated
+// The attempt fails when the game type is not in the DefinedGames, as its codes can not be inflated
+func LockedGenerationAttempt(gameType game.GameType, locks game.Locks, random *rand.Rand) (game.Board, bool) {
+	key, ok := game.DefinedGames.KeyOf(gameType)
+	if !ok {
+		return game.Board{}, false
+	}
+	code := game.GenerateLockedGameCode(gameType, locks, random)
+	board, err := game.DefinedGames[key].Inflate(code)
+	if err != nil {
+		return game.Board{}, false
+	}
+	board.Harbors = harborsOnBoard(board.Board)
+	return board, true
+}
+
+// harborsOnBoard returns the harbors of the tiles, keyed by their position
+func harborsOnBoard(tiles map[string][]*model.Tile) map[string]*model.Harbor {
+	harbors := make(map[string]*model.Harbor)
+	for column, columnTiles := range tiles {
+		for row, tile := range columnTiles {
+			if tile.Harbor != *model.HarborNone {
+				harbor := tile.Harbor
+				harbors[fmt.Sprintf("%s%d", column, row)] = &harbor
+			}
+		}
+	}
+	return harbors
+}
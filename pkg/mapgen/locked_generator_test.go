@@ -0,0 +1,52 @@
+package mapgen
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockedGenerationAttempt(t *testing.T) {
+	locks, err := game.ParseLocks("c2:6z6,a0:harbor=0", game.NormalGame)
+	assert.NoError(t, err)
+
+	board, ok := LockedGenerationAttempt(game.NormalGame, locks, rand.New(rand.NewSource(1)))
+	assert.True(t, ok)
+	assert.True(t, locks.Matches(&board))
+	assert.Len(t, board.Harbors, game.NormalGame.HarborCount)
+	assert.Equal(t, "3:1", board.Harbors["a0"].Name)
+}
+
+func TestProcessMapGenerationRequestWithLocks(t *testing.T) {
+	locks, err := game.ParseLocks("c2:6z6,b1:number=8", game.LargeGame)
+	assert.NoError(t, err)
+
+	for _, strategy := range Strategies {
+		options := GenerationOptions{Seed: 5, Strategy: strategy, Locks: locks}
+		gameMap, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesLarge, options, model.RequestInfo{})
+		if assert.NoError(t, err, strategy) {
+			board, err := game.InflateGameFromCode(gameMap.GameCode)
+			assert.NoError(t, err)
+			assert.True(t, locks.Matches(&board), strategy)
+			assert.True(t, board.Validate(game.DefaultGameRulesLarge).Valid, strategy)
+		}
+	}
+}
+
+func TestOptimizeBoardKeepsLocks(t *testing.T) {
+	locks, err := game.ParseLocks("c2:6z6,a0:harbor=0,b1:number=8", game.NormalGame)
+	assert.NoError(t, err)
+	rules := game.DefaultGameRulesNormal
+	attempt := attemptForStrategy(GenerationOptions{Strategy: StrategyRandom, Locks: locks}, game.NormalGame, rules, false)
+	outcome, err := generateInParallel(context.Background(), 2, rules.Generations, rand.New(rand.NewSource(2)), attempt)
+	assert.NoError(t, err)
+
+	options := OptimizationOptions{Iterations: 500, Locks: locks}
+	optimized, optimization := OptimizeBoard(context.Background(), outcome.board, rules, options, rand.New(rand.NewSource(1)))
+	assert.NotZero(t, optimization.Accepted)
+	assert.True(t, locks.Matches(&optimized))
+}
@@ -1,35 +1,73 @@
 package mapgen
 
 import (
+	"context"
+
 	"github.com/joostvdg/cmg/pkg/game"
 	log "github.com/sirupsen/logrus"
 )
 
-func GenerateBoardByGameCode(rules game.GameRules) game.Board {
+// GenerateBoardByGameCode generates game codes and inflates them, until the inflated board satisfies the GameRules
+// The codes are generated for the game type of the rules, by shuffling the landscapes, numbers and harbors of the game type
+// that are not locked by the Locks of the GenerationOptions, with VariableHarbors the harbors are on drawn coastal edges
+// With a NumberPlacement other than the RandomNumbers, the numbers of every inflated board are placed again,
+// and the board is validated without the MinimumScore of the adjacent tiles
+// The codes are drawn from a random source based on the seed in the GenerationOptions, which is stored on the board
+// The generation stops when the context is done, such as when the client went away or the Timeout of the GenerationOptions has passed
+// When none of the boards within the Generations limit is valid, it returns ErrGenerationLimit with the last board
+func GenerateBoardByGameCode(ctx context.Context, rules game.GameRules, options GenerationOptions) (game.Board, error) {
 	log.Debug(" > GenerateBoardByGameCode start")
+	random, seed := NewRandom(options.Seed)
 	totalGenerations := 0
-	code := game.GenerateGameCodeNormalGame()
-	log.Debugf("GameCode: %v", code)
+	_, definedGame := game.DefinedGames.ForRules(rules)
+	gameType := definedGame.GameType
+	rules = placementRules(options.Numbers, rules)
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
 
 	var board game.Board
-	gameType := game.NormalGame
-	var err error
 	for i := 0; i < rules.Generations; i++ {
-		board, err = game.InflateNormalGameFromCode(code, &gameType)
+		if ctx.Err() != nil {
+			log.WithFields(log.Fields{
+				"Attempts": totalGenerations,
+				"Reason":   ctx.Err(),
+			}).Info("Stopped generating a map by game code")
+			return board, ctx.Err()
+		}
+		code, ok := generateGameCode(gameType, options.Locks, rules, random)
+		if !ok {
+			log.Debug("Harbors do not fit on the coast")
+			totalGenerations++
+			continue
+		}
+		log.Debugf("GameCode: %v", code)
+		inflated, err := definedGame.Inflate(code)
 		if err != nil {
 			log.Debugf("Board not inflated correctly %v", err)
+			totalGenerations++
 			continue
 		}
+		board = inflated
+		if numbersAgain(options.Numbers) {
+			options.Numbers.PlaceNumbers(&board, random)
+			code = board.GetGameCode(false)
+		}
 
 		if board.IsValid(rules, gameType) {
 			log.Info("Required iterations: ", totalGenerations)
 			board.GameCode = code
 			board.TotalGenerations = totalGenerations
-			return board
+			board.Seed = seed
+			return board, nil
 		}
 		totalGenerations++
-		code = game.GenerateGameCodeNormalGame()
 	}
+	board.Seed = seed
+	board.TotalGenerations = totalGenerations
 	log.Debug(" > GenerateBoardByGameCode finish")
-	return board
+	return board, ErrGenerationLimit
 }
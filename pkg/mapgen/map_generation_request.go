@@ -2,37 +2,45 @@ package mapgen
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/go-errors/errors"
 	"github.com/joostvdg/cmg/pkg/analytics"
 	"github.com/joostvdg/cmg/pkg/game"
 	"github.com/joostvdg/cmg/pkg/webserver/model"
 	log "github.com/sirupsen/logrus"
 )
 
-func ProcessMapGenerationRequest(rules game.GameRules, requestInfo model.RequestInfo) (model.Map, error) {
+// ProcessMapGenerationRequest generates maps until one satisfies the given GameRules, or the Generations limit is reached
+// The attempts are drawn from a random source based on the seed in the GenerationOptions, which is returned with the map
+// The attempts are spread over a pool of workers, which stop when a valid map is found, or when the context is done,
+// such as when the client went away or the Timeout of the GenerationOptions has passed
+// With an Optimization, the map is optimized after it is found, within the same context
+// With BestEffort, reaching the Generations limit results in the closest map and its validation report, instead of an error
+func ProcessMapGenerationRequest(ctx context.Context, rules game.GameRules, options GenerationOptions, requestInfo model.RequestInfo) (model.Map, error) {
 	start := time.Now()
+	random, seed := NewRandom(options.Seed)
 
 	log.WithFields(log.Fields{
 		"GameRules":  rules,
+		"Seed":       seed,
+		"Strategy":   options.Strategy,
 		"RequestId":  requestInfo.RequestId,
 		"RequestURI": requestInfo.RequestURI,
 		"HOST":       requestInfo.Host,
 		"RemoteAddr": requestInfo.RemoteAddr,
 	}).Info("Attempt to generate a fair map:")
 
-	gameType := game.NormalGame
-	if rules.GameType == 0 {
-		gameType = game.NormalGame
-	} else if rules.GameType == 1 {
-		gameType = game.LargeGame
-	}
+	_, definedGame := game.DefinedGames.ForRules(rules)
+	gameType := definedGame.GameType
+	rules = placementRules(options.Numbers, rules)
 
 	gameTypeTime := time.Now()
 	gameTypeElapsed := gameTypeTime.Sub(start)
@@ -40,26 +48,51 @@ func ProcessMapGenerationRequest(rules game.GameRules, requestInfo model.Request
 		"Duration": gameTypeElapsed,
 	}).Debug("Setup Game Type ")
 
-	verbose := false
-	totalGenerations := 1 // to avoid the case we generate a map at first go, and divide by zero later
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
 
-	board := MapGenerationAttempt(gameType, verbose)
-	elapsedGen, _ := time.ParseDuration("0ns")
-	for !board.IsValid(rules, gameType) {
-		totalGenerations++
-		if totalGenerations > rules.Generations {
-			return model.Map{}, errors.New("Stuck in generation loop")
-		}
-		startGen := time.Now()
-		board = MapGenerationAttempt(gameType, verbose)
-		finishGen := time.Now()
-		elapsedGen += finishGen.Sub(startGen)
+	attempt := attemptForStrategy(options, gameType, rules, false)
+	outcome, err := generateInParallel(ctx, defaultWorkers(), rules.Generations, random, attempt)
+	bestEffort := false
+	if errors.Is(err, ErrGenerationLimit) && options.BestEffort && outcome.found {
+		log.WithFields(log.Fields{
+			"RequestId": requestInfo.RequestId,
+			"Distance":  outcome.report.Distance(),
+		}).Info("No valid map found, returning the closest map")
+		bestEffort = true
+	} else if err != nil {
+		return model.Map{}, err
+	}
+	board := outcome.board
+	totalGenerations := outcome.attempts
+	elapsedGen := outcome.spent
+
+	var optimization *model.Optimization
+	if options.Optimization != nil && !bestEffort {
+		// the optimizer gets its own random source, as the one of the generation is drawn from by the workers
+		optimized, result := OptimizeBoard(ctx, board, rules, options.optimizationOptions(&board), rand.New(rand.NewSource(seed)))
+		board = optimized
+		optimization = &result
 	}
 
+	intersections, roads := board.Graph()
 	var content = model.Map{
-		GameType: gameType.Name,
-		Board:    board.Board,
-		GameCode: board.GetGameCode(requestInfo.Delimiter),
+		GameType:         gameType.Name,
+		Board:            board.Board,
+		HarborDirections: board.HarborDirections,
+		GameCode:         board.GetGameCode(requestInfo.Delimiter),
+		Seed:             seed,
+		Attempts:         totalGenerations,
+		Intersections:    intersections,
+		Roads:            roads,
+		Optimization:     optimization,
+	}
+	if bestEffort {
+		content.BestEffort = true
+		content.Validation = &outcome.report
 	}
 
 	t := time.Now()
@@ -69,6 +102,7 @@ func ProcessMapGenerationRequest(rules game.GameRules, requestInfo model.Request
 
 	log.WithFields(log.Fields{
 		"RequestId":              requestInfo.RequestId,
+		"Seed":                   seed,
 		"Total Generations":      totalGenerations,
 		"Avg. Creation Duration": avgDuration,
 		"Total Duration":         elapsed,
@@ -1,51 +1,72 @@
 package mapgen
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/getsentry/sentry-go"
 	"github.com/joostvdg/cmg/pkg/game"
 	"github.com/joostvdg/cmg/pkg/model"
 	log "github.com/sirupsen/logrus"
 	"math/rand"
+	"os"
+	"sort"
 	"strconv"
 	"time"
 )
 
 type Game int
 
-func GenerateMap(count int, loop bool, verbose bool, rules game.GameRules) {
+// GenerateMap generates one or more maps, and prints each of them with the print function, such as PrintToConsole
+// All maps in a run are drawn from the same random source, so a run can be repeated with the seed it logs
+// The Strategy of the options determines how a map is searched for, and with BestEffort the closest map is printed when none is valid
+// What is reported besides the maps, such as why the closest map is not valid, goes to standard error
+func GenerateMap(count int, loop bool, verbose bool, rules game.GameRules, options GenerationOptions, print func(board *game.Board)) {
 
-	maxGenerationAttempts := 2500
 	numberOfLoops := count
 	if !loop {
 		numberOfLoops = 1
 	}
 
-	var gameType game.GameType
-	if rules.GameType == 0 {
-		gameType = game.NormalGame
-	} else if rules.GameType == 1 {
-		gameType = game.LargeGame
-		maxGenerationAttempts = 5000 // it's more difficult
-	}
+	_, definedGame := game.DefinedGames.ForRules(rules)
+	gameType := definedGame.GameType
+	rules = placementRules(options.Numbers, rules)
+	// larger game types are more difficult, their default rules allow more attempts
+	maxGenerationAttempts := definedGame.Rules.Generations
+
+	random, seed := NewRandom(options.Seed)
+	log.WithFields(log.Fields{
+		"Seed":     seed,
+		"Strategy": options.Strategy,
+	}).Info("Generating map(s)")
 
 	failedGenerations := 0
-	totalGenerations := 0
-	board := MapGenerationAttempt(gameType, verbose)
+	attempt := attemptForStrategy(options, gameType, rules, verbose)
 	for i := 0; i < numberOfLoops; i++ {
-		totalGenerations++
-		for !board.IsValid(rules, gameType) {
-			if totalGenerations > maxGenerationAttempts {
-				sentry.CaptureMessage(fmt.Sprintf("Could not generate map of type %v, tried %v times", gameType, totalGenerations))
-				sentry.Flush(time.Second * 5)
-				log.Fatalf("Can not generate a map... (%v runs)\n", totalGenerations)
+		outcome, err := generateInParallel(context.Background(), defaultWorkers(), maxGenerationAttempts, random, attempt)
+		failedGenerations += outcome.attempts - 1
+		log.Debug(fmt.Sprintf("Loop %v::%v", i, failedGenerations))
+		if errors.Is(err, ErrGenerationLimit) && options.BestEffort && outcome.found {
+			print(&outcome.board)
+			fmt.Fprintf(os.Stderr, "No valid map found after %v runs, this is the closest map, which does not satisfy:\n", outcome.attempts)
+			for _, failure := range outcome.report.Failures() {
+				fmt.Fprintf(os.Stderr, " - %v: %v\n", failure.Rule, failure.Message)
 			}
-			log.Debug(fmt.Sprintf("Loop %v::%v", i, failedGenerations))
-			totalGenerations++
-			failedGenerations++
-			board = MapGenerationAttempt(gameType, verbose)
+			continue
 		}
-		board.PrintToConsole()
+		if err != nil {
+			sentry.CaptureMessage(fmt.Sprintf("Could not generate map of type %v, tried %v times", gameType, outcome.attempts))
+			sentry.Flush(time.Second * 5)
+			log.Fatalf("Can not generate a map... (%v runs)\n", outcome.attempts)
+		}
+		if options.Optimization == nil {
+			print(&outcome.board)
+			continue
+		}
+		board, optimization := OptimizeBoard(context.Background(), outcome.board, rules, options.optimizationOptions(&outcome.board), random)
+		print(&board)
+		fmt.Fprintf(os.Stderr, "Optimized for %v in %v iterations, from %.2f to %.2f: %.2f\n", optimization.Objective, optimization.Iterations,
+			optimization.InitialScore, optimization.FinalScore, optimization.Trajectory)
 	}
 	log.WithFields(log.Fields{
 		"Map Generation Loops":    numberOfLoops,
@@ -65,11 +86,12 @@ func debugLogDuration(start time.Time, logMessage string) {
 
 // MapGenerationAttempt attempts to generate a map for the specified game type
 // It is regarded as an attempt, as the randomization can produce maps that are not valid and thus discarded
-func MapGenerationAttempt(gameType game.GameType, verbose bool) game.Board {
+// All random choices are drawn from the given random source, so the same source state results in the same board
+func MapGenerationAttempt(gameType game.GameType, verbose bool, random *rand.Rand) game.Board {
 	start := time.Now()
 	log.Debug(" > Created a new board start")
 	tiles := generateTiles(gameType)
-	distributeNumbers(gameType, tiles)
+	distributeNumbers(gameType, tiles, random)
 	if verbose {
 		for _, tile := range tiles {
 			log.WithFields(log.Fields{
@@ -79,11 +101,12 @@ func MapGenerationAttempt(gameType game.GameType, verbose bool) game.Board {
 			}).Debug("Tile:")
 		}
 	}
-	boardMap := distributeTiles(gameType, tiles, verbose)
-	harborMap := distributeHarbors(gameType)
+	boardMap := distributeTiles(gameType, tiles, verbose, random)
+	harborMap := distributeHarbors(gameType, random)
 	updateTilesWithHarbors(boardMap, harborMap)
+	tiles = append(tiles, seaTiles(boardMap)...)
 
-	board := &game.Board{
+	board := game.Board{
 		Tiles:    tiles,
 		Board:    boardMap,
 		GameType: gameType,
@@ -91,7 +114,7 @@ func MapGenerationAttempt(gameType game.GameType, verbose bool) game.Board {
 	}
 
 	debugLogDuration(start, " < Created a new board finish")
-	return *board
+	return board
 }
 
 func updateTilesWithHarbors(tiles map[string][]*model.Tile, harbors map[string]*model.Harbor) {
@@ -117,11 +140,25 @@ func generateTiles(gameType game.GameType) []*model.Tile {
 	tiles = append(tiles, addTilesOfType(gameType.MountainCount, *model.Mountain)...)
 	tiles = append(tiles, addTilesOfType(gameType.PastureCount, *model.Pasture)...)
 	tiles = append(tiles, addTilesOfType(gameType.RiverCount, *model.Hill)...)
+	tiles = append(tiles, addTilesOfType(gameType.GoldCount, *model.GoldField)...)
 
 	debugLogDuration(start, " - generateTiles")
 	return tiles
 }
 
+// seaTiles returns the Sea tiles placed on the board, they are not part of the shuffled tiles, positions without a tile are skipped
+func seaTiles(tiles map[string][]*model.Tile) []*model.Tile {
+	sea := make([]*model.Tile, 0)
+	for _, column := range tiles {
+		for _, tile := range column {
+			if tile != nil && tile.Landscape.Code == model.Sea.Code {
+				sea = append(sea, tile)
+			}
+		}
+	}
+	return sea
+}
+
 func addTilesOfType(numberOfTiles int, landscape model.Landscape) []*model.Tile {
 	start := time.Now()
 	tiles := make([]*model.Tile, numberOfTiles, numberOfTiles)
@@ -140,17 +177,17 @@ func addTilesOfType(numberOfTiles int, landscape model.Landscape) []*model.Tile
 	return tiles
 }
 
-func distributeNumbers(game game.GameType, tileSet []*model.Tile) {
+func distributeNumbers(game game.GameType, tileSet []*model.Tile, random *rand.Rand) {
 	start := time.Now()
-	numbersAllocated := make([]int, 0, game.TilesCount-game.DesertCount)
-	randomRange := game.TilesCount - game.DesertCount // desert tile doesn't get a number
+	randomRange := len(game.NumberSet) // desert tile doesn't get a number
+	numbersAllocated := make([]int, 0, randomRange)
 	log.Debug("Allocating numbers to Tiles")
-	for i := 0; i < game.TilesCount; i++ {
+	for i := 0; i < len(tileSet); i++ {
 		if tileSet[i].Landscape == *model.Desert {
 			tileSet[i].Number = *model.NumberEmpty
 			continue
 		}
-		drawnNumber := drawTileNumber(randomRange, numbersAllocated)
+		drawnNumber := drawTileNumber(random, randomRange, numbersAllocated)
 		number := game.NumberSet[drawnNumber]
 		numbersAllocated = append(numbersAllocated, drawnNumber)
 		tileSet[i].Number = *number
@@ -159,17 +196,37 @@ func distributeNumbers(game game.GameType, tileSet []*model.Tile) {
 	debugLogDuration(start, " - distributeNumbers")
 }
 
-func distributeTiles(gameType game.GameType, tileSet []*model.Tile, verbose bool) map[string][]*model.Tile {
+func distributeTiles(gameType game.GameType, tileSet []*model.Tile, verbose bool, random *rand.Rand) map[string][]*model.Tile {
 	start := time.Now()
 	var tilesOnBoard map[string][]*model.Tile
 	tilesOnBoard = make(map[string][]*model.Tile)
 
-	randomRange := gameType.TilesCount
-	numbersAllocated := make([]int, 0, gameType.TilesCount)
-	for gridLane, tilesInLane := range gameType.BoardLayout {
+	seaPositions := make(map[string]bool)
+	for _, position := range gameType.SeaLayout {
+		seaPositions[position] = true
+	}
+
+	randomRange := len(tileSet)
+	numbersAllocated := make([]int, 0, len(tileSet))
+	// walk the lanes in a fixed order, map iteration order would make the result depend on more than the random source
+	gridLanes := make([]string, 0, len(gameType.BoardLayout))
+	for gridLane := range gameType.BoardLayout {
+		gridLanes = append(gridLanes, gridLane)
+	}
+	sort.Strings(gridLanes)
+	for _, gridLane := range gridLanes {
+		tilesInLane := gameType.BoardLayout[gridLane]
 		tilesLine := make([]*model.Tile, tilesInLane, tilesInLane)
 		for i := 0; i < tilesInLane; i++ {
-			drawnTileNumber := drawTileNumber(randomRange, numbersAllocated)
+			if seaPositions[fmt.Sprintf("%s%d", gridLane, i)] {
+				tilesLine[i] = &model.Tile{
+					Landscape: *model.Sea,
+					Number:    *model.NumberEmpty,
+					Harbor:    *model.HarborNone,
+				}
+				continue
+			}
+			drawnTileNumber := drawTileNumber(random, randomRange, numbersAllocated)
 			tile := tileSet[drawnTileNumber]
 			numbersAllocated = append(numbersAllocated, drawnTileNumber)
 			tilesLine[i] = tile
@@ -185,7 +242,7 @@ func distributeTiles(gameType game.GameType, tileSet []*model.Tile, verbose bool
 	return tilesOnBoard
 }
 
-func distributeHarbors(gameType game.GameType) map[string]*model.Harbor {
+func distributeHarbors(gameType game.GameType, random *rand.Rand) map[string]*model.Harbor {
 	start := time.Now()
 	var harborsOnBoard map[string]*model.Harbor
 	harborsOnBoard = make(map[string]*model.Harbor)
@@ -193,7 +250,7 @@ func distributeHarbors(gameType game.GameType) map[string]*model.Harbor {
 	randomRange := gameType.HarborCount
 	numbersAllocated := make([]int, 0, gameType.HarborCount)
 	for _, positions := range gameType.HarborLayout {
-		drawnNumber := drawTileNumber(randomRange, numbersAllocated)
+		drawnNumber := drawTileNumber(random, randomRange, numbersAllocated)
 		harbor := gameType.HarborSet[drawnNumber]
 		numbersAllocated = append(numbersAllocated, drawnNumber)
 		harborsOnBoard[positions] = harbor
@@ -203,11 +260,10 @@ func distributeHarbors(gameType game.GameType) map[string]*model.Harbor {
 	return harborsOnBoard
 }
 
-func drawTileNumber(randomRange int, numbersAllocated []int) int {
-	rand.Seed(time.Now().UnixNano())
-	number := rand.Intn(randomRange)
+func drawTileNumber(random *rand.Rand, randomRange int, numbersAllocated []int) int {
+	number := random.Intn(randomRange)
 	for numberIsAllocated(number, numbersAllocated) {
-		number = rand.Intn(randomRange)
+		number = random.Intn(randomRange)
 	}
 	return number
 }
@@ -1,13 +1,16 @@
 package mapgen
 
 import (
+	"context"
 	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
 	"testing"
 )
 
 func TestGameCode(t *testing.T) {
-	board := MapGenerationAttempt(game.NormalGame, false)
+	board := MapGenerationAttempt(game.NormalGame, false, rand.New(rand.NewSource(1)))
 	gameCode := board.GetGameCode(false)
 	expectedLengthOfGameCode := game.NormalGame.TilesCount * 3 // landscape, number, harbor
 	assert.Equal(t, expectedLengthOfGameCode, len(gameCode))
@@ -18,7 +21,7 @@ func TestGameCode(t *testing.T) {
 }
 
 func TestGameCodeWithDelimiter(t *testing.T) {
-	board := MapGenerationAttempt(game.NormalGame, false)
+	board := MapGenerationAttempt(game.NormalGame, false, rand.New(rand.NewSource(1)))
 	gameCode := board.GetGameCode(true)
 	expectedLengthOfGameCode := game.NormalGame.TilesCount*3 + len(board.Board) // landscape, number, harbor + rows
 	assert.Equal(t, expectedLengthOfGameCode, len(gameCode))
@@ -29,7 +32,7 @@ func TestGameCodeWithDelimiter(t *testing.T) {
 }
 
 func TestGameCodeLage(t *testing.T) {
-	board := MapGenerationAttempt(game.NormalGame, false)
+	board := MapGenerationAttempt(game.NormalGame, false, rand.New(rand.NewSource(1)))
 	gameCode := board.GetGameCode(false)
 	expectedLengthOfGameCode := game.NormalGame.TilesCount * 3 // landscape, number, harbor
 	assert.Equal(t, expectedLengthOfGameCode, len(gameCode))
@@ -40,7 +43,7 @@ func TestGameCodeLage(t *testing.T) {
 }
 
 func TestGameCodeWithDelimiterLage(t *testing.T) {
-	board := MapGenerationAttempt(game.LargeGame, false)
+	board := MapGenerationAttempt(game.LargeGame, false, rand.New(rand.NewSource(1)))
 	gameCode := board.GetGameCode(true)
 	expectedLengthOfGameCode := game.LargeGame.TilesCount*3 + len(board.Board) // landscape, number, harbor + rows
 	assert.Equal(t, expectedLengthOfGameCode, len(gameCode))
@@ -49,3 +52,69 @@ func TestGameCodeWithDelimiterLage(t *testing.T) {
 	assert.NotEmpty(t, inflatedBoard)
 	assert.Empty(t, err)
 }
+
+func TestSameSeedGeneratesSameBoard(t *testing.T) {
+	boardA := MapGenerationAttempt(game.NormalGame, false, rand.New(rand.NewSource(42)))
+	boardB := MapGenerationAttempt(game.NormalGame, false, rand.New(rand.NewSource(42)))
+	assert.Equal(t, boardA.GetGameCode(false), boardB.GetGameCode(false))
+}
+
+func TestSameSeedGeneratesSameMap(t *testing.T) {
+	options := GenerationOptions{Seed: 1234}
+	mapA, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesNormal, options, model.RequestInfo{})
+	assert.NoError(t, err)
+	mapB, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesNormal, options, model.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), mapA.Seed)
+	assert.Equal(t, mapA.GameCode, mapB.GameCode)
+}
+
+func TestRandomSeedIsReported(t *testing.T) {
+	gameMap, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesNormal, GenerationOptions{}, model.RequestInfo{})
+	assert.NoError(t, err)
+	assert.NotZero(t, gameMap.Seed)
+
+	reproduced, err := ProcessMapGenerationRequest(context.Background(), game.DefaultGameRulesNormal, GenerationOptions{Seed: gameMap.Seed}, model.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, gameMap.GameCode, reproduced.GameCode)
+}
+
+func TestSameSeedGeneratesSameBoardByGameCode(t *testing.T) {
+	options := GenerationOptions{Seed: 99}
+	boardA, err := GenerateBoardByGameCode(context.Background(), game.DefaultGameRulesNormal, options)
+	assert.NoError(t, err)
+	boardB, err := GenerateBoardByGameCode(context.Background(), game.DefaultGameRulesNormal, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(99), boardA.Seed)
+	assert.Equal(t, boardA.GameCode, boardB.GameCode)
+}
+
+func TestGenerateBoardByGameCodeReportsGenerationLimit(t *testing.T) {
+	rules := game.DefaultGameRulesNormal
+	rules.MinimumScore = 999
+	rules.Generations = 5
+	board, err := GenerateBoardByGameCode(context.Background(), rules, GenerationOptions{Seed: 99})
+	assert.ErrorIs(t, err, ErrGenerationLimit)
+	assert.Empty(t, board.GameCode)
+	assert.Equal(t, 5, board.TotalGenerations)
+}
+
+func TestGenerateBoardByGameCodeStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := GenerateBoardByGameCode(ctx, game.DefaultGameRulesNormal, GenerationOptions{Seed: 99})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGameCodeSeafarers(t *testing.T) {
+	for name, gameType := range game.SeafarersGames {
+		board := MapGenerationAttempt(gameType, false, rand.New(rand.NewSource(1)))
+		gameCode := board.GetGameCode(false)
+		expectedLengthOfGameCode := gameType.TilesCount * 3 // landscape, number, harbor
+		assert.Equal(t, expectedLengthOfGameCode, len(gameCode), name)
+		assert.Equal(t, gameType.TilesCount, len(board.Tiles), name)
+		inflatedBoard, err := game.InflateSeafarersGameFromCode(gameCode)
+		assert.NoError(t, err, name)
+		assert.Equal(t, gameType.Name, inflatedBoard.GameType.Name, name)
+	}
+}
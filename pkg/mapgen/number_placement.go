@@ -0,0 +1,289 @@
+package mapgen
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// NumberPlacement how the numbers of a game type are placed on the land tiles of a board
+// The strategies place the numbers at random while they generate a board, any other placement places them again afterwards
+type NumberPlacement interface {
+	// PlaceNumbers gives every land tile of a complete board a number of its game type, except for the deserts
+	PlaceNumbers(board *game.Board, random *rand.Rand)
+}
+
+// RandomNumbers places the numbers at random, as the strategies do while they generate a board
+type RandomNumbers struct{}
+
+// SpiralNumbers places the numbers in the alphabetical order of their tokens, as in the setup of the official rules:
+// along a spiral that starts at a random corner of the board and runs counterclockwise towards the center, skipping the deserts
+// Game types without an alphabetical order of their own place the numbers in the order of their NumberSet
+type SpiralNumbers struct{}
+
+// NumberPlacements the number placements, by the name with which they are selected
+var NumberPlacements = map[string]NumberPlacement{
+	"random": RandomNumbers{},
+	"spiral": SpiralNumbers{},
+}
+
+// alphabeticalNumbers the numbers on the tokens A to R of the Normal game, and A to Zb of the Large game
+var alphabeticalNumbers = map[string][]int{
+	game.NormalGame.Name: {5, 2, 6, 3, 8, 10, 9, 12, 11, 4, 8, 10, 9, 4, 5, 6, 3, 11},
+	game.LargeGame.Name:  {2, 5, 4, 6, 3, 9, 8, 11, 11, 10, 6, 3, 8, 4, 8, 10, 11, 12, 10, 5, 4, 9, 5, 9, 12, 3, 2, 6},
+}
+
+// ParseNumberPlacement returns the NumberPlacement with the given name, an empty name is the RandomNumbers
+func ParseNumberPlacement(name string) (NumberPlacement, bool) {
+	if name == "" {
+		return RandomNumbers{}, true
+	}
+	placement, ok := NumberPlacements[name]
+	return placement, ok
+}
+
+// VerifyNumberLocks returns an error when the Locks lock a number, while the numbers are placed by a NumberPlacement
+// other than the RandomNumbers, as such a placement decides where every number goes
+func VerifyNumberLocks(placement NumberPlacement, locks game.Locks) error {
+	if numbersAgain(placement) && locks.LocksNumbers() {
+		return errors.New("the numbers can not be locked when they are placed in alphabetical order")
+	}
+	return nil
+}
+
+// PlaceNumbers draws a number for every land tile except the deserts, in the order of their positions
+func (RandomNumbers) PlaceNumbers(board *game.Board, random *rand.Rand) {
+	tiles := make([]*model.Tile, 0, len(board.Tiles))
+	for _, position := range board.Positions() {
+		if tile := tileAt(board.Board, position); tile.Landscape != *model.Sea {
+			tiles = append(tiles, tile)
+		}
+	}
+	distributeNumbers(board.GameType, tiles, random)
+	board.GameCode = ""
+}
+
+// PlaceNumbers places the numbers in alphabetical order along the spiral from a random corner
+func (SpiralNumbers) PlaceNumbers(board *game.Board, random *rand.Rand) {
+	positions := spiralPositions(board.GameType, random)
+	numbers := spiralNumbers(board.GameType)
+	placed := 0
+	for _, position := range positions {
+		tile := tileAt(board.Board, position)
+		if tile.Landscape == *model.Desert {
+			tile.Number = *model.NumberEmpty
+			continue
+		}
+		tile.Number = *numbers[placed]
+		placed++
+	}
+	board.GameCode = ""
+}
+
+// numbersAgain whether the numbers of the generated boards have to be placed again, which is for every placement
+// except the RandomNumbers, as the strategies already place the numbers at random
+func numbersAgain(placement NumberPlacement) bool {
+	if placement == nil {
+		return false
+	}
+	_, random := placement.(RandomNumbers)
+	return !random
+}
+
+// placementRules returns the rules to validate the boards with for the NumberPlacement, which for a placement other than
+// the RandomNumbers do not hold the groups of adjacent tiles to the MinimumScore: the fixed order of the numbers decides
+// the score of the groups where the order starts, such as the 2, 5 and 4 in a corner of the Large game, which no board can raise
+func placementRules(placement NumberPlacement, rules game.GameRules) game.GameRules {
+	if numbersAgain(placement) {
+		rules.MinimumScore = 0
+	}
+	return rules
+}
+
+// numberPlacementAttempt returns an attempt that places the numbers on the board of the attempt, and validates it again
+func numberPlacementAttempt(placement NumberPlacement, rules game.GameRules, attempt generationAttempt) generationAttempt {
+	return func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+		board, _, ok := attempt(random)
+		if !ok {
+			return board, game.ValidationReport{}, false
+		}
+		placement.PlaceNumbers(&board, random)
+		return board, board.Validate(rules), true
+	}
+}
+
+// numberLocks returns the locks with the number of every land tile of the board locked as well,
+// so the optimizer keeps the numbers where the NumberPlacement placed them
+func numberLocks(board *game.Board, locks game.Locks) game.Locks {
+	numbers := make(game.Locks, len(board.Tiles))
+	for position, lock := range locks {
+		numbers[position] = lock
+	}
+	for _, position := range board.Positions() {
+		if tile := tileAt(board.Board, position); tile.Landscape != *model.Sea {
+			lock := numbers[position]
+			lock.Number = tile.Number.Code
+			numbers[position] = lock
+		}
+	}
+	return numbers
+}
+
+// spiralNumbers returns the numbers of the game type in alphabetical order, or in the order of its NumberSet
+// when it has no alphabetical order, or one that does not have the same numbers as its NumberSet
+func spiralNumbers(gameType game.GameType) []*model.Number {
+	values, ok := alphabeticalNumbers[gameType.Name]
+	if !ok || len(values) != len(gameType.NumberSet) {
+		return gameType.NumberSet
+	}
+	available := append([]*model.Number(nil), gameType.NumberSet...)
+	numbers := make([]*model.Number, 0, len(values))
+	for _, value := range values {
+		found := false
+		for i, number := range available {
+			if number.Number == value {
+				numbers = append(numbers, number)
+				available = append(available[:i], available[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return gameType.NumberSet
+		}
+	}
+	return numbers
+}
+
+// spiralPositions returns the positions of the land tiles along a spiral, which starts at a random corner of the board
+// and runs counterclockwise along the coast, then along each ring within it, towards the center
+// The corners are the tiles on the coast with the most neighbours that are not land
+func spiralPositions(gameType game.GameType, random *rand.Rand) []string {
+	sea := make(map[string]bool)
+	for _, position := range gameType.SeaLayout {
+		sea[position] = true
+	}
+	remaining := make(map[model.Hex]string)
+	for position, hex := range gameType.Hexes {
+		if !sea[position] {
+			remaining[hex] = position
+		}
+	}
+
+	corners := outerRing(remaining)
+	sort.Slice(corners, func(i, j int) bool {
+		return landNeighbours(corners[i], remaining) < landNeighbours(corners[j], remaining) ||
+			(landNeighbours(corners[i], remaining) == landNeighbours(corners[j], remaining) && corners[i].Less(corners[j]))
+	})
+	cornerCount := 1
+	for cornerCount < len(corners) && landNeighbours(corners[cornerCount], remaining) == landNeighbours(corners[0], remaining) {
+		cornerCount++
+	}
+	start := corners[random.Intn(cornerCount)]
+
+	positions := make([]string, 0, len(remaining))
+	for len(remaining) > 0 {
+		ring := outerRing(remaining)
+		walked := walkCounterclockwise(ring, start)
+		for _, hex := range walked {
+			positions = append(positions, remaining[hex])
+		}
+		for _, hex := range ring {
+			delete(remaining, hex)
+		}
+		if len(remaining) == 0 {
+			break
+		}
+		start = nextRingStart(outerRing(remaining), walked[len(walked)-1], walked[0])
+	}
+	return positions
+}
+
+// outerRing returns the hexes that do not have all six neighbours among the hexes, ordered by their coordinates
+func outerRing(hexes map[model.Hex]string) []model.Hex {
+	ring := make([]model.Hex, 0)
+	for hex := range hexes {
+		if landNeighbours(hex, hexes) < len(model.HexDirections) {
+			ring = append(ring, hex)
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		return ring[i].Less(ring[j])
+	})
+	return ring
+}
+
+func landNeighbours(hex model.Hex, hexes map[model.Hex]string) int {
+	count := 0
+	for _, neighbour := range hex.Neighbours() {
+		if _, ok := hexes[neighbour]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+// walkCounterclockwise walks the ring from the start, each step to the neighbour that lies furthest counterclockwise
+// around the center of the ring, and jumps to the closest hex of the ring that is not walked yet when there is no such neighbour
+func walkCounterclockwise(ring []model.Hex, start model.Hex) []model.Hex {
+	centerX, centerY := 0.0, 0.0
+	for _, hex := range ring {
+		x, y := hex.Center()
+		centerX += x / float64(len(ring))
+		centerY += y / float64(len(ring))
+	}
+	// the Y of the plane runs from top to bottom, so counterclockwise on the board is a negative cross product
+	cross := func(from model.Hex, to model.Hex) float64 {
+		fromX, fromY := from.Center()
+		toX, toY := to.Center()
+		return (fromX-centerX)*(toY-centerY) - (fromY-centerY)*(toX-centerX)
+	}
+
+	walked := make([]model.Hex, 0, len(ring))
+	visited := make(map[model.Hex]bool)
+	current := start
+	for {
+		walked = append(walked, current)
+		visited[current] = true
+		if len(walked) == len(ring) {
+			return walked
+		}
+
+		found := false
+		var next model.Hex
+		for _, hex := range ring {
+			if visited[hex] || !current.IsNeighbour(hex) {
+				continue
+			}
+			if !found || cross(current, hex) < cross(current, next) {
+				next = hex
+				found = true
+			}
+		}
+		if !found {
+			for _, hex := range ring {
+				if !visited[hex] && (!found || current.Distance(hex) < current.Distance(next)) {
+					next = hex
+					found = true
+				}
+			}
+		}
+		current = next
+	}
+}
+
+// nextRingStart returns where the spiral continues on the next ring, the hex closest to the last hex of the previous ring,
+// and of those the one closest to where the previous ring started
+func nextRingStart(ring []model.Hex, last model.Hex, previousStart model.Hex) model.Hex {
+	start := ring[0]
+	for _, hex := range ring[1:] {
+		if last.Distance(hex) < last.Distance(start) ||
+			(last.Distance(hex) == last.Distance(start) && previousStart.Distance(hex) < previousStart.Distance(start)) {
+			start = hex
+		}
+	}
+	return start
+}
@@ -0,0 +1,156 @@
+package mapgen
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+	webmodel "github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpiralPositions(t *testing.T) {
+	for _, gameType := range []game.GameType{game.NormalGame, game.LargeGame} {
+		random := rand.New(rand.NewSource(1))
+		for i := 0; i < 10; i++ {
+			positions := spiralPositions(gameType, random)
+			assert.Len(t, positions, gameType.TilesCount, gameType.Name)
+			assert.ElementsMatch(t, codePositionsOf(gameType), positions, gameType.Name)
+			// the spiral starts at a corner, and every step is to a neighbouring tile
+			assert.Equal(t, 3, landNeighbours(gameType.Hexes[positions[0]], landHexesOf(gameType)), gameType.Name)
+			for j := 1; j < len(positions); j++ {
+				assert.True(t, gameType.Hexes[positions[j-1]].IsNeighbour(gameType.Hexes[positions[j]]), positions)
+			}
+		}
+	}
+}
+
+func TestSpiralPositionsCounterclockwise(t *testing.T) {
+	normal := landHexesOf(game.NormalGame)
+	walked := walkCounterclockwise(outerRing(normal), game.NormalGame.Hexes["a0"])
+	positions := make([]string, 0, len(walked))
+	for _, hex := range walked {
+		positions = append(positions, normal[hex])
+	}
+	// from the top of the left column, down the left side of the board
+	assert.Equal(t, []string{"a0", "a1", "a2", "b3", "c4", "d3", "e2", "e1", "e0", "d0", "c0", "b0"}, positions)
+}
+
+func TestSpiralNumbers(t *testing.T) {
+	for _, gameType := range []game.GameType{game.NormalGame, game.LargeGame} {
+		random := rand.New(rand.NewSource(1))
+		board := MapGenerationAttempt(gameType, false, random)
+		SpiralNumbers{}.PlaceNumbers(&board, random)
+
+		placed := make([]int, 0, len(gameType.NumberSet))
+		for _, position := range spiralPositions(gameType, rand.New(rand.NewSource(1))) {
+			tile := tileAt(board.Board, position)
+			if tile.Landscape == *model.Desert {
+				assert.Equal(t, *model.NumberEmpty, tile.Number, position)
+			}
+		}
+		for _, tile := range board.Tiles {
+			if tile.Landscape != *model.Desert {
+				placed = append(placed, tile.Number.Number)
+			}
+		}
+		assert.ElementsMatch(t, alphabeticalNumbers[gameType.Name], placed, gameType.Name)
+		assert.True(t, game.ValidateTilesNumbers(&board, game.GameRules{}).Valid, gameType.Name)
+	}
+}
+
+func TestSpiralNumbersInAlphabeticalOrder(t *testing.T) {
+	board := MapGenerationAttempt(game.LargeGame, false, rand.New(rand.NewSource(1)))
+	SpiralNumbers{}.PlaceNumbers(&board, rand.New(rand.NewSource(2)))
+
+	numbers := make([]int, 0, len(game.LargeGame.NumberSet))
+	for _, position := range spiralPositions(game.LargeGame, rand.New(rand.NewSource(2))) {
+		if tile := tileAt(board.Board, position); tile.Landscape != *model.Desert {
+			numbers = append(numbers, tile.Number.Number)
+		}
+	}
+	assert.Equal(t, alphabeticalNumbers[game.LargeGame.Name], numbers)
+}
+
+func TestProcessMapGenerationRequestWithSpiralNumbers(t *testing.T) {
+	for _, rules := range []game.GameRules{game.DefaultGameRulesNormal, game.DefaultGameRulesLarge} {
+		for _, strategy := range Strategies {
+			options := GenerationOptions{Seed: 3, Strategy: strategy, Numbers: SpiralNumbers{}}
+			options.Optimization = &OptimizationOptions{Iterations: 200}
+			gameMap, err := ProcessMapGenerationRequest(context.Background(), rules, options, webmodel.RequestInfo{})
+			if !assert.NoError(t, err, "%s %s", rules.GameTypeString, strategy) {
+				continue
+			}
+			board, err := game.InflateGameFromCode(gameMap.GameCode)
+			if assert.NoError(t, err, "%s %s", rules.GameTypeString, strategy) {
+				assert.Equal(t, rules.GameTypeString, board.GameType.Name)
+				assert.True(t, inSpiralOrder(&board), "%s %s", rules.GameTypeString, strategy)
+			}
+		}
+
+		board, err := GenerateBoardByGameCode(context.Background(), rules, GenerationOptions{Seed: 3, Numbers: SpiralNumbers{}})
+		if !assert.NoError(t, err, rules.GameTypeString) {
+			continue
+		}
+		assert.True(t, inSpiralOrder(&board), rules.GameTypeString)
+		inflated, err := game.InflateGameFromCode(board.GameCode)
+		if assert.NoError(t, err, rules.GameTypeString) {
+			assert.True(t, inSpiralOrder(&inflated), rules.GameTypeString)
+		}
+	}
+}
+
+func TestPlacementRules(t *testing.T) {
+	rules := game.DefaultGameRulesLarge
+	assert.Equal(t, rules, placementRules(nil, rules))
+	assert.Equal(t, rules, placementRules(RandomNumbers{}, rules))
+
+	spiral := placementRules(SpiralNumbers{}, rules)
+	assert.Equal(t, 0, spiral.MinimumScore)
+	assert.Equal(t, rules.MaximumScore, spiral.MaximumScore)
+}
+
+func TestVerifyNumberLocks(t *testing.T) {
+	locks, err := game.ParseLocks("c2:6z6,b1:number=8", game.NormalGame)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyNumberLocks(RandomNumbers{}, locks))
+	assert.Error(t, VerifyNumberLocks(SpiralNumbers{}, locks))
+
+	locks, err = game.ParseLocks("c2:6z6", game.NormalGame)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyNumberLocks(SpiralNumbers{}, locks))
+}
+
+// inSpiralOrder returns whether the numbers of the board are in alphabetical order along the spiral from any corner
+func inSpiralOrder(board *game.Board) bool {
+	for seed := int64(0); seed < 50; seed++ {
+		numbers := make([]int, 0, len(board.GameType.NumberSet))
+		for _, position := range spiralPositions(board.GameType, rand.New(rand.NewSource(seed))) {
+			if tile := tileAt(board.Board, position); tile.Landscape != *model.Desert {
+				numbers = append(numbers, tile.Number.Number)
+			}
+		}
+		if assert.ObjectsAreEqual(alphabeticalNumbers[board.GameType.Name], numbers) {
+			return true
+		}
+	}
+	return false
+}
+
+func codePositionsOf(gameType game.GameType) []string {
+	positions := make([]string, 0, len(gameType.Hexes))
+	for position := range gameType.Hexes {
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+func landHexesOf(gameType game.GameType) map[model.Hex]string {
+	hexes := make(map[model.Hex]string, len(gameType.Hexes))
+	for position, hex := range gameType.Hexes {
+		hexes[hex] = position
+	}
+	return hexes
+}
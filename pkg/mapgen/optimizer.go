@@ -0,0 +1,290 @@
+package mapgen
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+	webmodel "github.com/joostvdg/cmg/pkg/webserver/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Objective what the optimizer minimizes to get a more balanced board
+type Objective string
+
+const (
+	// ObjectiveIntersectionVariance the variance of the scores of the intersections, so no place to build stands out
+	ObjectiveIntersectionVariance Objective = "intersectionVariance"
+	// ObjectiveResourceSpread the difference between the highest and lowest average score of the resources
+	ObjectiveResourceSpread Objective = "resourceSpread"
+	// ObjectiveMaxTileGroup the highest score of three adjacent tiles
+	ObjectiveMaxTileGroup Objective = "maxTileGroup"
+)
+
+// Objectives the objectives a board can be optimized for
+var Objectives = []Objective{ObjectiveIntersectionVariance, ObjectiveResourceSpread, ObjectiveMaxTileGroup}
+
+const (
+	// DefaultOptimizationIterations the number of swaps the optimizer tries, when none is given
+	DefaultOptimizationIterations = 2000
+	// trajectoryPoints the number of times the score is sampled during an optimization
+	trajectoryPoints = 20
+)
+
+// OptimizationOptions how to optimize a board, an empty Objective means the ObjectiveIntersectionVariance
+// The swaps that move a locked element of the Locks are undone
+type OptimizationOptions struct {
+	Objective  Objective
+	Iterations int
+	Locks      game.Locks
+}
+
+// ParseObjective returns the Objective with the given name, an empty name is the ObjectiveIntersectionVariance
+func ParseObjective(name string) (Objective, bool) {
+	if name == "" {
+		return ObjectiveIntersectionVariance, true
+	}
+	for _, objective := range Objectives {
+		if string(objective) == name {
+			return objective, true
+		}
+	}
+	return "", false
+}
+
+// ObjectiveScore scores the board for the objective, the lower the score the more balanced the board
+func ObjectiveScore(board *game.Board, objective Objective) float64 {
+	switch objective {
+	case ObjectiveResourceSpread:
+		return resourceSpread(board)
+	case ObjectiveMaxTileGroup:
+		return maxTileGroup(board)
+	default:
+		return intersectionVariance(board)
+	}
+}
+
+func intersectionVariance(board *game.Board) float64 {
+	intersections, _ := board.Graph()
+	if len(intersections) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, intersection := range intersections {
+		total += float64(intersection.Score)
+	}
+	mean := total / float64(len(intersections))
+	variance := 0.0
+	for _, intersection := range intersections {
+		variance += math.Pow(float64(intersection.Score)-mean, 2)
+	}
+	return variance / float64(len(intersections))
+}
+
+func resourceSpread(board *game.Board) float64 {
+	scores := make(map[string]int)
+	counts := make(map[string]int)
+	for _, column := range board.Board {
+		for _, tile := range column {
+			if tile.Number.Pips() == 0 {
+				continue
+			}
+			scores[tile.Landscape.Resource.Code] += tile.Number.Score
+			counts[tile.Landscape.Resource.Code]++
+		}
+	}
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, resource := range []*model.Resource{model.Lumber, model.Wool, model.Grain, model.Brick, model.Ore} {
+		if counts[resource.Code] == 0 {
+			continue
+		}
+		average := float64(scores[resource.Code]) / float64(counts[resource.Code])
+		lowest = math.Min(lowest, average)
+		highest = math.Max(highest, average)
+	}
+	if math.IsInf(lowest, 1) {
+		return 0
+	}
+	return highest - lowest
+}
+
+func maxTileGroup(board *game.Board) float64 {
+	highest := 0
+	for _, tileGroup := range board.GameType.AdjacentTileGroups {
+		score := 0
+		for _, position := range tileGroup {
+			score += tileAt(board.Board, position).Number.Score
+		}
+		if score > highest {
+			highest = score
+		}
+	}
+	return float64(highest)
+}
+
+// swap an exchange of two tiles, numbers or harbors between two positions, which can be undone by doing it again
+type swap func(board *game.Board)
+
+// OptimizeBoard improves the balance of a valid board by simulated annealing
+// Each iteration swaps two tiles, two numbers or two harbors, a swap that breaks one of the GameRules is undone,
+// a swap that improves the score is kept, and a swap that worsens it is kept with a chance that drops over time,
+// so the optimizer can get out of a local optimum early on
+// It returns the best board it found, and stops early when the context is done
+func OptimizeBoard(ctx context.Context, board game.Board, rules game.GameRules, options OptimizationOptions, random *rand.Rand) (game.Board, webmodel.Optimization) {
+	start := time.Now()
+	objective, _ := ParseObjective(string(options.Objective))
+	iterations := options.Iterations
+	if iterations <= 0 {
+		iterations = DefaultOptimizationIterations
+	}
+
+	current := copyBoard(board)
+	currentScore := ObjectiveScore(&current, objective)
+	best := copyBoard(current)
+	bestScore := currentScore
+	optimization := webmodel.Optimization{
+		Objective:    string(objective),
+		InitialScore: currentScore,
+		Trajectory:   []float64{currentScore},
+	}
+
+	landPositions := make([]string, 0, len(current.Tiles))
+	for _, position := range current.Positions() {
+		if tileAt(current.Board, position).Landscape != *model.Sea {
+			landPositions = append(landPositions, position)
+		}
+	}
+	harborPositions := make([]string, 0, len(current.Harbors))
+	for _, position := range current.Positions() {
+		if _, ok := current.Harbors[position]; ok {
+			harborPositions = append(harborPositions, position)
+		}
+	}
+
+	// start warm enough to accept a swap that is a tenth of the initial score worse
+	initialTemperature := math.Max(currentScore/10, 1)
+	sampleEvery := int(math.Max(1, float64(iterations)/trajectoryPoints))
+	for i := 0; i < iterations; i++ {
+		if ctx.Err() != nil {
+			log.WithFields(log.Fields{
+				"Iterations": i,
+				"Reason":     ctx.Err(),
+			}).Info("Stopped optimizing the map")
+			break
+		}
+		optimization.Iterations++
+
+		move := randomSwap(random, landPositions, harborPositions)
+		move(&current)
+		if options.Locks.Matches(&current) && satisfies(&current, rules) {
+			score := ObjectiveScore(&current, objective)
+			temperature := initialTemperature * (1 - float64(i)/float64(iterations))
+			if score <= currentScore || random.Float64() < math.Exp((currentScore-score)/temperature) {
+				currentScore = score
+				optimization.Accepted++
+				if score < bestScore {
+					best = copyBoard(current)
+					bestScore = score
+				}
+			} else {
+				move(&current)
+			}
+		} else {
+			move(&current)
+		}
+
+		if (i+1)%sampleEvery == 0 {
+			optimization.Trajectory = append(optimization.Trajectory, bestScore)
+		}
+	}
+	optimization.FinalScore = bestScore
+
+	log.WithFields(log.Fields{
+		"Objective":     objective,
+		"Iterations":    optimization.Iterations,
+		"Accepted":      optimization.Accepted,
+		"Initial Score": optimization.InitialScore,
+		"Final Score":   optimization.FinalScore,
+		"Duration":      time.Since(start),
+	}).Info("Optimized a map")
+	return best, optimization
+}
+
+// randomSwap picks a swap of two land tiles, of the numbers of two land tiles, or of two harbors
+func randomSwap(random *rand.Rand, landPositions []string, harborPositions []string) swap {
+	kind := random.Intn(3)
+	if kind == 2 && len(harborPositions) < 2 {
+		kind = random.Intn(2)
+	}
+	switch kind {
+	case 0:
+		a, b := pickTwo(random, landPositions)
+		return func(board *game.Board) {
+			tileA, tileB := tileAt(board.Board, a), tileAt(board.Board, b)
+			tileA.Landscape, tileB.Landscape = tileB.Landscape, tileA.Landscape
+			tileA.Number, tileB.Number = tileB.Number, tileA.Number
+		}
+	case 1:
+		a, b := pickTwo(random, landPositions)
+		return func(board *game.Board) {
+			tileA, tileB := tileAt(board.Board, a), tileAt(board.Board, b)
+			// a Desert keeps its empty number
+			if tileA.Landscape == *model.Desert || tileB.Landscape == *model.Desert {
+				return
+			}
+			tileA.Number, tileB.Number = tileB.Number, tileA.Number
+		}
+	default:
+		a, b := pickTwo(random, harborPositions)
+		return func(board *game.Board) {
+			board.Harbors[a], board.Harbors[b] = board.Harbors[b], board.Harbors[a]
+			tileAt(board.Board, a).Harbor = *board.Harbors[a]
+			tileAt(board.Board, b).Harbor = *board.Harbors[b]
+		}
+	}
+}
+
+func pickTwo(random *rand.Rand, positions []string) (string, string) {
+	a := random.Intn(len(positions))
+	b := random.Intn(len(positions) - 1)
+	if b >= a {
+		b++
+	}
+	return positions[a], positions[b]
+}
+
+// satisfies returns whether the board does not violate any of the Validations, checked one after the other
+func satisfies(board *game.Board, rules game.GameRules) bool {
+	for _, validation := range game.Validations {
+		if !validation(board, rules).Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// copyBoard copies the tiles of a board, so they can be changed without changing the original board
+func copyBoard(board game.Board) game.Board {
+	copied := board
+	copied.GameCode = ""
+	copied.Board = make(map[string][]*model.Tile, len(board.Board))
+	copied.Tiles = make([]*model.Tile, 0, len(board.Tiles))
+	for column, tiles := range board.Board {
+		copied.Board[column] = make([]*model.Tile, len(tiles))
+		for row, tile := range tiles {
+			tileCopy := *tile
+			copied.Board[column][row] = &tileCopy
+		}
+	}
+	for _, position := range copied.Positions() {
+		copied.Tiles = append(copied.Tiles, tileAt(copied.Board, position))
+	}
+	copied.Harbors = make(map[string]*model.Harbor, len(board.Harbors))
+	for position, harbor := range board.Harbors {
+		copied.Harbors[position] = harbor
+	}
+	return copied
+}
@@ -0,0 +1,86 @@
+package mapgen
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func optimizerTestBoard(t *testing.T) game.Board {
+	board, _, err := GenerateBoardByBacktracking(game.NormalGame, game.DefaultGameRulesNormal, rand.New(rand.NewSource(11)))
+	assert.NoError(t, err)
+	return board
+}
+
+// tileCodes returns the codes of the landscapes and of the numbers on the board, which do not change by swapping them
+func tileCodes(board game.Board) ([]string, []string) {
+	landscapes := make([]string, 0, len(board.Tiles))
+	numbers := make([]string, 0, len(board.Tiles))
+	for _, tile := range board.Tiles {
+		landscapes = append(landscapes, tile.Landscape.Code)
+		numbers = append(numbers, tile.Number.Code)
+	}
+	sort.Strings(landscapes)
+	sort.Strings(numbers)
+	return landscapes, numbers
+}
+
+func TestOptimizeBoard(t *testing.T) {
+	board := optimizerTestBoard(t)
+	code := board.GetGameCode(false)
+	rules := game.DefaultGameRulesNormal
+
+	for _, objective := range Objectives {
+		options := OptimizationOptions{Objective: objective, Iterations: 500}
+		optimized, optimization := OptimizeBoard(context.Background(), board, rules, options, rand.New(rand.NewSource(1)))
+
+		assert.Equal(t, string(objective), optimization.Objective)
+		assert.Equal(t, 500, optimization.Iterations)
+		assert.LessOrEqual(t, optimization.FinalScore, optimization.InitialScore, objective)
+		assert.Equal(t, ObjectiveScore(&optimized, objective), optimization.FinalScore, objective)
+		assert.Equal(t, trajectoryPoints+1, len(optimization.Trajectory), objective)
+		assert.True(t, optimized.Validate(rules).Valid, objective)
+		landscapes, numbers := tileCodes(board)
+		optimizedLandscapes, optimizedNumbers := tileCodes(optimized)
+		assert.Equal(t, landscapes, optimizedLandscapes, objective)
+		assert.Equal(t, numbers, optimizedNumbers, objective)
+		assert.Equal(t, len(board.Harbors), len(optimized.Harbors), objective)
+	}
+	// the board that is optimized is not changed
+	assert.Equal(t, code, board.GetGameCode(false))
+}
+
+func TestOptimizeBoardIsReproducible(t *testing.T) {
+	board := optimizerTestBoard(t)
+	options := OptimizationOptions{Objective: ObjectiveMaxTileGroup, Iterations: 300}
+	optimizedA, _ := OptimizeBoard(context.Background(), board, game.DefaultGameRulesNormal, options, rand.New(rand.NewSource(3)))
+	optimizedB, _ := OptimizeBoard(context.Background(), board, game.DefaultGameRulesNormal, options, rand.New(rand.NewSource(3)))
+	assert.Equal(t, optimizedA.GetGameCode(false), optimizedB.GetGameCode(false))
+}
+
+func TestOptimizeBoardStopsOnCancel(t *testing.T) {
+	board := optimizerTestBoard(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	optimized, optimization := OptimizeBoard(ctx, board, game.DefaultGameRulesNormal, OptimizationOptions{}, rand.New(rand.NewSource(3)))
+	assert.Equal(t, 0, optimization.Iterations)
+	assert.Equal(t, string(ObjectiveIntersectionVariance), optimization.Objective)
+	assert.Equal(t, board.GetGameCode(false), optimized.GetGameCode(false))
+}
+
+func TestParseObjective(t *testing.T) {
+	objective, ok := ParseObjective("")
+	assert.True(t, ok)
+	assert.Equal(t, ObjectiveIntersectionVariance, objective)
+
+	objective, ok = ParseObjective("resourceSpread")
+	assert.True(t, ok)
+	assert.Equal(t, ObjectiveResourceSpread, objective)
+
+	_, ok = ParseObjective("fairness")
+	assert.False(t, ok)
+}
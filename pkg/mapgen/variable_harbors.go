@@ -0,0 +1,36 @@
+package mapgen
+
+import (
+	"math/rand"
+
+	"github.com/joostvdg/cmg/pkg/game"
+)
+
+// variableHarborAttempt returns an attempt that draws the coastal edges of the harbors, and makes the attempt of the strategy
+// with those harbor positions as the HarborLayout
+// The attempt fails when the harbors do not fit on the coast
+func variableHarborAttempt(gameType game.GameType, locks game.Locks, rules game.GameRules, attemptFor func(harborGameType game.GameType) generationAttempt) generationAttempt {
+	return func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+		directions, ok := game.DrawHarborDirections(gameType, locks, random)
+		if !ok {
+			return game.Board{}, game.ValidationReport{}, false
+		}
+		board, _, ok := attemptFor(game.WithHarborDirections(gameType, directions))(random)
+		if !ok {
+			return board, game.ValidationReport{}, false
+		}
+		board.GameType = gameType
+		board.HarborDirections = directions
+		return board, board.Validate(rules), true
+	}
+}
+
+// generateGameCode generates a game code for the game type around the locked tiles, with the harbors on drawn coastal edges
+// for VariableHarbors
+// Returns false when the harbors do not fit on the coast
+func generateGameCode(gameType game.GameType, locks game.Locks, rules game.GameRules, random *rand.Rand) (string, bool) {
+	if rules.VariableHarbors {
+		return game.GenerateVariableHarborGameCode(gameType, locks, random)
+	}
+	return game.GenerateLockedGameCode(gameType, locks, random), true
+}
@@ -0,0 +1,46 @@
+package mapgen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessMapGenerationRequestWithVariableHarbors(t *testing.T) {
+	rules := game.DefaultGameRulesNormal
+	rules.VariableHarbors = true
+	locks, err := game.ParseLocks("c2:6z6", game.NormalGame)
+	assert.NoError(t, err)
+
+	for _, strategy := range Strategies {
+		for _, options := range []GenerationOptions{{Seed: 3, Strategy: strategy}, {Seed: 3, Strategy: strategy, Locks: locks}} {
+			gameMap, err := ProcessMapGenerationRequest(context.Background(), rules, options, model.RequestInfo{})
+			if !assert.NoError(t, err, strategy) {
+				continue
+			}
+			assert.Len(t, gameMap.HarborDirections, game.NormalGame.HarborCount, strategy)
+			board, err := game.InflateGameFromCode(gameMap.GameCode)
+			if assert.NoError(t, err, strategy) {
+				assert.Equal(t, gameMap.HarborDirections, board.HarborDirections, strategy)
+				assert.True(t, board.Validate(rules).Valid, strategy)
+				assert.True(t, options.Locks.Matches(&board), strategy)
+			}
+		}
+	}
+}
+
+func TestGenerateBoardByGameCodeWithVariableHarbors(t *testing.T) {
+	rules := game.DefaultGameRulesLarge
+	rules.VariableHarbors = true
+	board, err := GenerateBoardByGameCode(context.Background(), rules, GenerationOptions{Seed: 3})
+	assert.NoError(t, err)
+	assert.Len(t, board.HarborDirections, game.LargeGame.HarborCount)
+	assert.True(t, board.Validate(rules).Valid)
+
+	inflated, err := game.InflateGameFromCode(board.GameCode)
+	assert.NoError(t, err)
+	assert.Equal(t, board.HarborDirections, inflated.HarborDirections)
+}
@@ -0,0 +1,196 @@
+package mapgen
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/joostvdg/cmg/pkg/game"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrGenerationLimit is returned when none of the attempts within the Generations limit resulted in a valid map
+var ErrGenerationLimit = errors.New("Stuck in generation loop")
+
+// generationAttempt generates a single board from the random source, and validates it against the GameRules
+// An attempt that did not complete a board, such as a backtracking attempt that gave up, returns false
+type generationAttempt func(random *rand.Rand) (game.Board, game.ValidationReport, bool)
+
+// generationJob an attempt to hand to a worker, with the seed for its own random source
+type generationJob struct {
+	index int
+	seed  int64
+}
+
+// generationResult the outcome of an attempt done by a worker
+type generationResult struct {
+	index    int
+	board    game.Board
+	report   game.ValidationReport
+	complete bool
+	duration time.Duration
+}
+
+// generationOutcome the board found by generateInParallel, with its validation report
+// Attempts is the number of attempts up to and including the valid one, and Spent the time spent in all attempts
+// Without a valid board, the board is the closest one that was generated, if any was completed
+type generationOutcome struct {
+	board    game.Board
+	report   game.ValidationReport
+	found    bool
+	attempts int
+	spent    time.Duration
+}
+
+// attemptForStrategy returns how a single attempt is made for the Strategy of the GenerationOptions,
+// or around the locked tiles when there are Locks
+// With VariableHarbors, every attempt first draws the coastal edges of its harbors,
+// and with a NumberPlacement other than the RandomNumbers, every attempt places the numbers on its board again
+// Verbose logs the tiles of the attempts of the StrategyRandom
+func attemptForStrategy(options GenerationOptions, gameType game.GameType, rules game.GameRules, verbose bool) generationAttempt {
+	attempt := strategyAttempt(options.Strategy, options.Locks, gameType, rules, verbose)
+	if rules.VariableHarbors {
+		attempt = variableHarborAttempt(gameType, options.Locks, rules, func(harborGameType game.GameType) generationAttempt {
+			return strategyAttempt(options.Strategy, options.Locks, harborGameType, rules, verbose)
+		})
+	}
+	if numbersAgain(options.Numbers) {
+		return numberPlacementAttempt(options.Numbers, rules, attempt)
+	}
+	return attempt
+}
+
+// strategyAttempt returns how a single attempt is made for the Strategy, with the harbors on the HarborLayout of the game type
+func strategyAttempt(strategy Strategy, locks game.Locks, gameType game.GameType, rules game.GameRules, verbose bool) generationAttempt {
+	if len(locks) > 0 {
+		return func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+			board, ok := LockedGenerationAttempt(gameType, locks, random)
+			if !ok {
+				return board, game.ValidationReport{}, false
+			}
+			return board, board.Validate(rules), true
+		}
+	}
+	if strategy == StrategyBacktracking {
+		return func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+			board, ok := BacktrackingGenerationAttempt(gameType, rules, random)
+			if !ok {
+				return board, game.ValidationReport{}, false
+			}
+			return board, board.Validate(rules), true
+		}
+	}
+	return func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+		board := MapGenerationAttempt(gameType, verbose, random)
+		return board, board.Validate(rules), true
+	}
+}
+
+// generateInParallel runs up to limit attempts on a pool of workers, and returns the first valid board
+// Every attempt has its own random source, seeded in order from the given random source,
+// and the first valid board is the one of the first attempt in that order, not the one that finished first,
+// so the outcome does not depend on the number of workers or how they are scheduled
+// All workers are stopped as soon as the outcome is known, or when the context is done
+// When the limit is reached, it returns ErrGenerationLimit with the board that is closest to valid,
+// the one with the smallest distance in its validation report, and of those the first in order
+func generateInParallel(ctx context.Context, workers int, limit int, random *rand.Rand, attempt generationAttempt) (generationOutcome, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan generationJob)
+	defer close(jobs)
+	results := make(chan generationResult, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range jobs {
+				start := time.Now()
+				board, report, complete := attempt(rand.New(rand.NewSource(job.seed)))
+				result := generationResult{index: job.index, board: board, report: report, complete: complete, duration: time.Since(start)}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	var outcome generationOutcome
+	var spent time.Duration
+	first := -1
+	closest := -1
+	next := generationJob{index: 0, seed: random.Int63()}
+	running := make(map[int]bool)
+	for {
+		if first >= 0 && !runningBefore(running, first) {
+			outcome.attempts = first + 1
+			outcome.spent = spent
+			return outcome, nil
+		}
+		if first < 0 && next.index >= limit && len(running) == 0 {
+			outcome.attempts = limit
+			outcome.spent = spent
+			return outcome, ErrGenerationLimit
+		}
+
+		// there is no use in attempts after a valid one, but the attempts before it still have to finish
+		var nextJobs chan generationJob
+		if first < 0 && next.index < limit {
+			nextJobs = jobs
+		}
+		select {
+		case nextJobs <- next:
+			running[next.index] = true
+			// the seed is only drawn once the previous job is handed out, so the seeds do not depend on the timing
+			next = generationJob{index: next.index + 1, seed: random.Int63()}
+		case result := <-results:
+			delete(running, result.index)
+			spent += result.duration
+			if !result.complete {
+				continue
+			}
+			if result.report.Valid && (first < 0 || result.index < first) {
+				first = result.index
+				outcome.board, outcome.report, outcome.found = result.board, result.report, true
+			}
+			if first < 0 && closer(result, closest, outcome.report) {
+				closest = result.index
+				outcome.board, outcome.report, outcome.found = result.board, result.report, true
+			}
+		case <-ctx.Done():
+			log.WithFields(log.Fields{
+				"Attempts": next.index,
+				"Reason":   ctx.Err(),
+			}).Info("Stopped generating a map")
+			return generationOutcome{attempts: next.index, spent: spent}, ctx.Err()
+		}
+	}
+}
+
+// closer returns whether the result is closer to a valid board than the closest one so far, at the index with the report
+func closer(result generationResult, closest int, report game.ValidationReport) bool {
+	if closest < 0 {
+		return true
+	}
+	if result.report.Distance() != report.Distance() {
+		return result.report.Distance() < report.Distance()
+	}
+	return result.index < closest
+}
+
+// runningBefore returns whether any of the running attempts comes before the index
+func runningBefore(running map[int]bool, index int) bool {
+	for running := range running {
+		if running < index {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultWorkers the number of workers to generate maps with, one for each processor Go may use
+func defaultWorkers() int {
+	return runtime.GOMAXPROCS(0)
+}
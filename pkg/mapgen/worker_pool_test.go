@@ -0,0 +1,102 @@
+package mapgen
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// seedAttempt is valid for about one in ten attempts, and marks the board with a value of its random source
+func seedAttempt(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+	board := game.Board{Seed: random.Int63()}
+	return board, game.ValidationReport{Valid: random.Intn(10) == 0}, true
+}
+
+// distanceAttempt is never valid, and is a random distance away from being valid
+func distanceAttempt(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+	board := game.Board{Seed: random.Int63()}
+	result := game.ValidationResult{Rule: "Distance", Value: random.Intn(1000)}
+	return board, game.ValidationReport{Results: []game.ValidationResult{result}}, true
+}
+
+func TestParallelGenerationDoesNotDependOnWorkers(t *testing.T) {
+	outcomeA, err := generateInParallel(context.Background(), 1, 1000, rand.New(rand.NewSource(5)), seedAttempt)
+	assert.NoError(t, err)
+	outcomeB, err := generateInParallel(context.Background(), 8, 1000, rand.New(rand.NewSource(5)), seedAttempt)
+	assert.NoError(t, err)
+	assert.True(t, outcomeA.found)
+	assert.Equal(t, outcomeA.board.Seed, outcomeB.board.Seed)
+	assert.Equal(t, outcomeA.attempts, outcomeB.attempts)
+}
+
+func TestParallelGenerationKeepsClosestBoard(t *testing.T) {
+	outcomeA, err := generateInParallel(context.Background(), 1, 200, rand.New(rand.NewSource(5)), distanceAttempt)
+	assert.ErrorIs(t, err, ErrGenerationLimit)
+	outcomeB, err := generateInParallel(context.Background(), 8, 200, rand.New(rand.NewSource(5)), distanceAttempt)
+	assert.ErrorIs(t, err, ErrGenerationLimit)
+
+	assert.True(t, outcomeA.found)
+	assert.Less(t, outcomeA.report.Distance(), 50)
+	assert.Equal(t, outcomeA.board.Seed, outcomeB.board.Seed)
+	assert.Equal(t, outcomeA.report.Distance(), outcomeB.report.Distance())
+}
+
+func TestParallelGenerationLimit(t *testing.T) {
+	var attempts int32
+	never := func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+		atomic.AddInt32(&attempts, 1)
+		return game.Board{}, game.ValidationReport{}, false
+	}
+	outcome, err := generateInParallel(context.Background(), 4, 50, rand.New(rand.NewSource(1)), never)
+	assert.ErrorIs(t, err, ErrGenerationLimit)
+	assert.False(t, outcome.found)
+	assert.Equal(t, 50, outcome.attempts)
+	assert.Equal(t, int32(50), atomic.LoadInt32(&attempts))
+}
+
+func TestParallelGenerationStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	never := func(random *rand.Rand) (game.Board, game.ValidationReport, bool) {
+		return game.Board{}, game.ValidationReport{}, false
+	}
+	_, err := generateInParallel(ctx, 4, 1000000, rand.New(rand.NewSource(1)), never)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGenerationRequestStopsOnTimeout(t *testing.T) {
+	rules := game.DefaultGameRulesNormal
+	rules.MinimumScore = 1000 // impossible
+	rules.Generations = 1000000
+	options := GenerationOptions{Seed: 1, Timeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, err := ProcessMapGenerationRequest(context.Background(), rules, options, model.RequestInfo{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGenerationRequestBestEffort(t *testing.T) {
+	rules := game.DefaultGameRulesNormal
+	rules.MinimumScore = 1000 // impossible
+	rules.Generations = 100
+
+	_, err := ProcessMapGenerationRequest(context.Background(), rules, GenerationOptions{Seed: 1}, model.RequestInfo{})
+	assert.ErrorIs(t, err, ErrGenerationLimit)
+
+	gameMap, err := ProcessMapGenerationRequest(context.Background(), rules, GenerationOptions{Seed: 1, BestEffort: true}, model.RequestInfo{})
+	if assert.NoError(t, err) {
+		assert.True(t, gameMap.BestEffort)
+		assert.Equal(t, 57, len(gameMap.GameCode))
+		if assert.NotNil(t, gameMap.Validation) {
+			assert.False(t, gameMap.Validation.Valid)
+			assert.Positive(t, gameMap.Validation.Distance())
+		}
+	}
+}
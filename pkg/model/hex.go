@@ -0,0 +1,158 @@
+package model
+
+import "math"
+
+// Hex the position of a tile on a grid of hexagons with a flat top, in axial coordinates
+// Q is the column from left to right, R runs from top to bottom within a column,
+// so the tile below (Q, R) is (Q, R+1) and the tile to the lower right is (Q+1, R)
+// The third cube coordinate S is implied, as Q + R + S is always 0
+type Hex struct {
+	Q int
+	R int
+}
+
+// HexVertex an intersection on the grid, the corner where three hexes meet
+// Some of these hexes can lie outside a board, such as for the intersections on the coast
+type HexVertex [3]Hex
+
+// HexEdge the side that two neighbouring hexes share
+type HexEdge [2]Hex
+
+// HexDirections the offsets of the six neighbours of a hex, counterclockwise starting at the lower right
+// The neighbours in two consecutive directions are also neighbours of each other
+var HexDirections = [6]Hex{
+	{Q: 1, R: 0},  // lower right
+	{Q: 1, R: -1}, // upper right
+	{Q: 0, R: -1}, // above
+	{Q: -1, R: 0}, // upper left
+	{Q: -1, R: 1}, // lower left
+	{Q: 0, R: 1},  // below
+}
+
+// S the third cube coordinate of the hex
+func (h Hex) S() int {
+	return -h.Q - h.R
+}
+
+// Add returns the hex at the offset from this hex
+func (h Hex) Add(offset Hex) Hex {
+	return Hex{Q: h.Q + offset.Q, R: h.R + offset.R}
+}
+
+// Neighbour returns the neighbouring hex in one of the HexDirections
+func (h Hex) Neighbour(direction int) Hex {
+	return h.Add(HexDirections[direction%6])
+}
+
+// Neighbours returns all six neighbouring hexes, in the order of the HexDirections
+func (h Hex) Neighbours() [6]Hex {
+	var neighbours [6]Hex
+	for direction := range HexDirections {
+		neighbours[direction] = h.Neighbour(direction)
+	}
+	return neighbours
+}
+
+// Distance returns the number of steps from this hex to the other hex
+func (h Hex) Distance(other Hex) int {
+	return (abs(h.Q-other.Q) + abs(h.R-other.R) + abs(h.S()-other.S())) / 2
+}
+
+// IsNeighbour whether the other hex shares an edge with this hex
+func (h Hex) IsNeighbour(other Hex) bool {
+	return h.Distance(other) == 1
+}
+
+// Vertices returns the six corners of the hex, the corner in direction i lies between the neighbours i and i+1
+func (h Hex) Vertices() [6]HexVertex {
+	var vertices [6]HexVertex
+	for direction := range HexDirections {
+		vertices[direction] = NewHexVertex(h, h.Neighbour(direction), h.Neighbour(direction+1))
+	}
+	return vertices
+}
+
+// Edges returns the six sides of the hex, in the order of the HexDirections
+func (h Hex) Edges() [6]HexEdge {
+	var edges [6]HexEdge
+	for direction := range HexDirections {
+		edges[direction] = NewHexEdge(h, h.Neighbour(direction))
+	}
+	return edges
+}
+
+// Center returns the center of the hex on a plane, for hexes with sides of length one
+// X runs from left to right, Y from top to bottom
+func (h Hex) Center() (float64, float64) {
+	return 1.5 * float64(h.Q), math.Sqrt(3) * (float64(h.R) + float64(h.Q)/2)
+}
+
+// Less orders hexes by column, then from top to bottom
+func (h Hex) Less(other Hex) bool {
+	if h.Q != other.Q {
+		return h.Q < other.Q
+	}
+	return h.R < other.R
+}
+
+// NewHexVertex creates the vertex where three hexes meet, with the hexes in a fixed order so equal vertices compare equal
+func NewHexVertex(a Hex, b Hex, c Hex) HexVertex {
+	vertex := HexVertex{a, b, c}
+	for i := 1; i < len(vertex); i++ {
+		for j := i; j > 0 && vertex[j].Less(vertex[j-1]); j-- {
+			vertex[j], vertex[j-1] = vertex[j-1], vertex[j]
+		}
+	}
+	return vertex
+}
+
+// NewHexEdge creates the edge between two neighbouring hexes, with the hexes in a fixed order so equal edges compare equal
+func NewHexEdge(a Hex, b Hex) HexEdge {
+	if b.Less(a) {
+		return HexEdge{b, a}
+	}
+	return HexEdge{a, b}
+}
+
+// Vertices returns the two intersections at the ends of the edge
+func (e HexEdge) Vertices() [2]HexVertex {
+	var vertices [2]HexVertex
+	found := 0
+	for _, neighbour := range e[0].Neighbours() {
+		if neighbour.IsNeighbour(e[1]) {
+			vertices[found] = NewHexVertex(e[0], e[1], neighbour)
+			found++
+		}
+	}
+	return vertices
+}
+
+// Less orders vertices by their hexes
+func (v HexVertex) Less(other HexVertex) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i].Less(other[i])
+		}
+	}
+	return false
+}
+
+// Less orders edges by their hexes
+func (e HexEdge) Less(other HexEdge) bool {
+	if e[0] != other[0] {
+		return e[0].Less(other[0])
+	}
+	return e[1].Less(other[1])
+}
+
+// Edges returns the three edges that meet in the intersection
+func (v HexVertex) Edges() [3]HexEdge {
+	return [3]HexEdge{NewHexEdge(v[0], v[1]), NewHexEdge(v[0], v[2]), NewHexEdge(v[1], v[2])}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexNeighbours(t *testing.T) {
+	hex := Hex{Q: 2, R: -1}
+	for _, neighbour := range hex.Neighbours() {
+		assert.Equal(t, 1, hex.Distance(neighbour))
+	}
+	assert.Equal(t, 0, hex.Q+hex.R+hex.S())
+	assert.Equal(t, 3, hex.Distance(Hex{Q: -1, R: 0}))
+}
+
+func TestHexVertices(t *testing.T) {
+	hex := Hex{Q: 0, R: 0}
+	vertices := hex.Vertices()
+	for direction, vertex := range vertices {
+		assert.Contains(t, vertex, hex)
+		// every corner is shared with the two neighbours it touches
+		assert.Contains(t, hex.Neighbour(direction).Vertices(), vertex)
+		assert.Contains(t, hex.Neighbour(direction+1).Vertices(), vertex)
+	}
+	assert.Equal(t, NewHexVertex(Hex{0, 0}, Hex{1, 0}, Hex{1, -1}), NewHexVertex(Hex{1, -1}, Hex{0, 0}, Hex{1, 0}))
+}
+
+func TestHexEdges(t *testing.T) {
+	hex := Hex{Q: 0, R: 0}
+	for direction, edge := range hex.Edges() {
+		assert.Equal(t, NewHexEdge(hex.Neighbour(direction), hex), edge)
+		ends := edge.Vertices()
+		assert.NotEqual(t, ends[0], ends[1])
+		for _, end := range ends {
+			assert.Contains(t, end.Edges(), edge)
+		}
+	}
+}
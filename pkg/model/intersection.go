@@ -0,0 +1,24 @@
+package model
+
+// Intersection the corner where up to three tiles meet, the place to build a settlement or city
+// Tiles contains the positions of the land tiles, an intersection on the coast touches one or two
+// Score and Pips are the totals of the numbers on these tiles, Resources holds the pips per resource
+type Intersection struct {
+	ID        int
+	Vertex    HexVertex
+	Tiles     []string
+	Score     int
+	Pips      int
+	Resources map[string]int
+	Coastal   bool
+	Harbor    *Harbor
+}
+
+// Road the edge between two tiles, the place to build a road between two intersections
+type Road struct {
+	ID            int
+	Edge          HexEdge
+	Tiles         []string
+	Intersections [2]int
+	Coastal       bool
+}
@@ -10,12 +10,14 @@ type Landscape struct {
 }
 
 var (
-	Desert   = &Landscape{Name: "Desert", Resource: *None}
-	Field    = &Landscape{Name: "Field", Resource: *Grain}
-	Forest   = &Landscape{Name: "Forest", Resource: *Lumber}
-	Pasture  = &Landscape{Name: "Pasture", Resource: *Wool}
-	Mountain = &Landscape{Name: "Mountain", Resource: *Ore}
-	Hill     = &Landscape{Name: "Hill", Resource: *Brick}
+	Desert    = &Landscape{Name: "Desert", Resource: *None}
+	Field     = &Landscape{Name: "Field", Resource: *Grain}
+	Forest    = &Landscape{Name: "Forest", Resource: *Lumber}
+	Pasture   = &Landscape{Name: "Pasture", Resource: *Wool}
+	Mountain  = &Landscape{Name: "Mountain", Resource: *Ore}
+	Hill      = &Landscape{Name: "Hill", Resource: *Brick}
+	Sea       = &Landscape{Name: "Sea", Resource: *Water}
+	GoldField = &Landscape{Name: "Gold Field", Resource: *Gold}
 
 	Landscapes = landscapes{
 		Grain.Code:  *Field,
@@ -24,5 +26,7 @@ var (
 		Wool.Code:   *Pasture,
 		Ore.Code:    *Mountain,
 		Brick.Code:  *Hill,
+		Water.Code:  *Sea,
+		Gold.Code:   *GoldField,
 	}
 )
@@ -39,3 +39,14 @@ var (
 		Number12.Code:    *Number12,
 	}
 )
+
+// Pips the number of dots on the number fiche, the number of the 36 dice combinations that roll the number
+func (n Number) Pips() int {
+	if n.Number < 2 || n.Number > 12 {
+		return 0
+	}
+	if n.Number < 7 {
+		return n.Number - 1
+	}
+	return 13 - n.Number
+}
@@ -12,5 +12,7 @@ var (
 	Grain  = &Resource{"Grain", "3"}
 	Brick  = &Resource{"Brick", "4"}
 	Ore    = &Resource{"Ore", "5"}
-	None   = &Resource{"None", "6"} // Desert
+	None   = &Resource{"None", "6"}  // Desert
+	Water  = &Resource{"Water", "7"} // Sea, produces nothing but separates islands
+	Gold   = &Resource{"Gold", "8"}  // Gold Field, produces a resource of choice
 )
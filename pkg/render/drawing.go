@@ -0,0 +1,224 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// point a point on the drawing, in pixels from the top left
+type point struct {
+	x float64
+	y float64
+}
+
+// tileShape the hex of a tile, with its number token and the pips below the number
+type tileShape struct {
+	position   string
+	landscape  string
+	colour     string
+	center     point
+	corners    [6]point
+	number     int
+	textColour string
+	pips       []point
+}
+
+// harborShape the marker of a harbor, connected by docks to the two ends of the coastal edge it faces
+type harborShape struct {
+	name   string
+	ratio  string
+	colour string
+	center point
+	docks  [2]point
+}
+
+// drawing the shapes of a board, and the size of the image they fit in
+type drawing struct {
+	width   float64
+	height  float64
+	tiles   []tileShape
+	harbors []harborShape
+}
+
+const (
+	// hexSize the length of the sides of a hex, in pixels
+	hexSize = 60.0
+	// margin the space around the tiles, which leaves room for the harbors
+	margin       = hexSize
+	tokenRadius  = hexSize * 0.38
+	numberSize   = hexSize * 0.36
+	pipRadius    = hexSize * 0.035
+	harborRadius = hexSize * 0.27
+	ratioSize    = hexSize * 0.22
+)
+
+const (
+	backgroundColour = "#1d5c85"
+	outlineColour    = "#3b2f23"
+	tokenColour      = "#f5ecd7"
+	textColour       = "#222222"
+	redColour        = "#c62828"
+	dockColour       = "#8d6e63"
+	harborAllColour  = "#ffffff"
+)
+
+// landscapeColours the colour of the tiles of a landscape, by the code of the landscape
+var landscapeColours = map[string]string{
+	model.Forest.Code:    "#2e7d32",
+	model.Pasture.Code:   "#9ccc65",
+	model.Field.Code:     "#f9c74f",
+	model.Hill.Code:      "#d9652b",
+	model.Mountain.Code:  "#8d99ae",
+	model.Desert.Code:    "#e9d8a6",
+	model.Sea.Code:       "#4fa3d1",
+	model.GoldField.Code: "#ffd60a",
+}
+
+// layout places the shapes of the board, the tiles in the order of their positions
+func layout(board *game.Board) drawing {
+	positions := board.Positions()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, position := range positions {
+		x, y := board.GameType.Hexes[position].Center()
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	if len(positions) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	// the centers are for hexes with sides of one, a hex is two wide and the square root of three high
+	offset := point{x: margin + hexSize - minX*hexSize, y: margin + hexSize*math.Sqrt(3)/2 - minY*hexSize}
+	toPixels := func(hex model.Hex) point {
+		x, y := hex.Center()
+		return point{x: x*hexSize + offset.x, y: y*hexSize + offset.y}
+	}
+
+	result := drawing{
+		width:  (maxX-minX)*hexSize + 2*hexSize + 2*margin,
+		height: (maxY-minY)*hexSize + math.Sqrt(3)*hexSize + 2*margin,
+		tiles:  make([]tileShape, 0, len(positions)),
+	}
+	for _, position := range positions {
+		tile := board.Tile(position)
+		center := toPixels(board.GameType.Hexes[position])
+		shape := tileShape{
+			position:   position,
+			landscape:  tile.Landscape.Name,
+			colour:     landscapeColours[tile.Landscape.Code],
+			center:     center,
+			textColour: textColour,
+		}
+		for i := range shape.corners {
+			angle := math.Pi / 3 * float64(i)
+			shape.corners[i] = point{x: center.x + hexSize*math.Cos(angle), y: center.y + hexSize*math.Sin(angle)}
+		}
+		if tile.Number.Pips() > 0 {
+			shape.number = tile.Number.Number
+			if tile.Number.Number == model.Number6.Number || tile.Number.Number == model.Number8.Number {
+				shape.textColour = redColour
+			}
+			pips := tile.Number.Pips()
+			for i := 0; i < pips; i++ {
+				shape.pips = append(shape.pips, point{
+					x: center.x + (float64(i)-float64(pips-1)/2)*pipRadius*2.6,
+					y: center.y + tokenRadius*0.6,
+				})
+			}
+		}
+		result.tiles = append(result.tiles, shape)
+	}
+
+	edges := board.HarborEdges()
+	for _, position := range positions {
+		edge, ok := edges[position]
+		if !ok {
+			continue
+		}
+		land, sea := board.GameType.Hexes[position], edge[0]
+		if sea == land {
+			sea = edge[1]
+		}
+		from, to := toPixels(land), toPixels(sea)
+		direction := point{x: (to.x - from.x) / (hexSize * math.Sqrt(3)), y: (to.y - from.y) / (hexSize * math.Sqrt(3))}
+		middle := point{x: (from.x + to.x) / 2, y: (from.y + to.y) / 2}
+
+		harbor := board.Tile(position).Harbor
+		shape := harborShape{
+			name:   harbor.Name,
+			ratio:  strings.Fields(harbor.Name)[0],
+			colour: harborColour(harbor),
+			center: point{x: from.x + (to.x-from.x)*0.85, y: from.y + (to.y-from.y)*0.85},
+			docks: [2]point{
+				{x: middle.x - direction.y*hexSize/2, y: middle.y + direction.x*hexSize/2},
+				{x: middle.x + direction.y*hexSize/2, y: middle.y - direction.x*hexSize/2},
+			},
+		}
+		result.harbors = append(result.harbors, shape)
+	}
+	return result
+}
+
+// harborColour the colour of the landscape that produces the resource the harbor trades, white for a 3:1 harbor
+func harborColour(harbor model.Harbor) string {
+	for code, landscape := range model.Landscapes {
+		if landscape.Resource == harbor.Resource {
+			return landscapeColours[code]
+		}
+	}
+	return harborAllColour
+}
+
+// rgb returns the colour in the #rrggbb notation of the drawing as an opaque colour
+func rgb(colour string) color.RGBA {
+	var red, green, blue uint8
+	fmt.Sscanf(colour, "#%02x%02x%02x", &red, &green, &blue)
+	return color.RGBA{R: red, G: green, B: blue, A: 0xff}
+}
+
+// painter paints the shapes of a drawing, in the points of the drawing
+type painter interface {
+	// polygon fills the polygon, and outlines it when the outline has a width
+	polygon(points []point, fill string, outline string, width float64)
+	// circle fills the circle, and outlines it when the outline has a width
+	circle(center point, radius float64, fill string, outline string, width float64)
+	// line paints a line of the width between the two points
+	line(from point, to point, width float64, colour string)
+	// text paints the numbers and trade ratios, centered on the point
+	text(text string, center point, size float64, colour string)
+}
+
+// paint paints the drawing in the same order as WriteSVG draws it: the docks below the tiles, the harbors on top
+func paint(drawing drawing, painter painter) {
+	painter.polygon([]point{{0, 0}, {drawing.width, 0}, {drawing.width, drawing.height}, {0, drawing.height}}, backgroundColour, "", 0)
+
+	for _, harbor := range drawing.harbors {
+		for _, dock := range harbor.docks {
+			painter.line(harbor.center, dock, hexSize/12, dockColour)
+		}
+	}
+
+	for _, tile := range drawing.tiles {
+		painter.polygon(tile.corners[:], tile.colour, outlineColour, 2)
+		if tile.number == 0 {
+			continue
+		}
+		painter.circle(tile.center, tokenRadius, tokenColour, outlineColour, 1)
+		painter.text(strconv.Itoa(tile.number), tile.center, numberSize, tile.textColour)
+		for _, pip := range tile.pips {
+			painter.circle(pip, pipRadius, tile.textColour, "", 0)
+		}
+	}
+
+	for _, harbor := range drawing.harbors {
+		painter.circle(harbor.center, harborRadius, harbor.colour, outlineColour, 2)
+		painter.text(harbor.ratio, harbor.center, ratioSize, textColour)
+	}
+}
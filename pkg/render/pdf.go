@@ -0,0 +1,367 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
+
+// ContentTypePDF the media type of the PDF table sheet of a board
+const ContentTypePDF = "application/pdf"
+
+const (
+	// pageWidth the width of an A4 page, in points
+	pageWidth = 595.0
+	// pageHeight the height of an A4 page, in points
+	pageHeight = 842.0
+	pageMargin = 48.0
+	// qrQuietZone the light modules around a QR code, which scanners need to find it
+	qrQuietZone = 4
+	qrSize      = 150.0
+)
+
+// the fonts of the pages, which are standard fonts that every PDF reader has
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	// fontCode has the same width for every character, 0.6 of the font size, which makes the codes easy to read and to wrap
+	fontCode = "F3"
+)
+
+// WritePDF writes the table sheet of the board as an A4 PDF document: a page with the board, its game code
+// and a QR code of the url of the game code, followed by the checklist of the tiles, numbers and harbors to set up
+func WritePDF(w io.Writer, board *game.Board, url string) error {
+	qr, err := encodeQR(url)
+	if err != nil {
+		return err
+	}
+	gameCode := board.GetGameCode(false)
+	pages := []*pdfPage{boardPage(board, gameCode, qr, url)}
+	pages = append(pages, checklistPages(board, gameCode)...)
+	return writeDocument(w, pages)
+}
+
+// boardPage the page with the board, scaled to fit between the game code and the QR code
+func boardPage(board *game.Board, gameCode string, qr qrCode, url string) *pdfPage {
+	page := &pdfPage{}
+	y := pageMargin + 20
+	page.text(fontBold, 20, point{x: pageMargin, y: y}, board.GameType.Name+" map")
+	y += 22
+	page.text(fontRegular, 10, point{x: pageMargin, y: y}, "Game code")
+	y = page.wrappedCode(10, point{x: pageMargin, y: y + 14}, gameCode)
+
+	drawing := layout(board)
+	top, bottom := y+12, pageHeight-pageMargin-qrSize-12
+	scale := math.Min((pageWidth-2*pageMargin)/drawing.width, (bottom-top)/drawing.height)
+	paint(drawing, &pdfBoard{
+		page:   page,
+		origin: point{x: (pageWidth - drawing.width*scale) / 2, y: top + (bottom-top-drawing.height*scale)/2},
+		scale:  scale,
+	})
+
+	corner := point{x: pageMargin, y: pageHeight - pageMargin - qrSize}
+	module := qrSize / float64(len(qr)+2*qrQuietZone)
+	page.fill(tokenColour)
+	page.rectangle(corner, qrSize, qrSize)
+	page.paint("f")
+	page.fill("#000000")
+	for row, modules := range qr {
+		for column, dark := range modules {
+			if dark {
+				// a little larger than the module, so neighbouring modules leave no seams
+				page.rectangle(point{
+					x: corner.x + float64(column+qrQuietZone)*module,
+					y: corner.y + float64(row+qrQuietZone)*module,
+				}, module+0.1, module+0.1)
+			}
+		}
+	}
+	page.paint("f")
+	page.fill(textColour)
+	page.text(fontRegular, 10, point{x: corner.x + qrSize + 16, y: corner.y + 20}, "Scan for the map of this game code")
+	page.wrappedCode(8, point{x: corner.x + qrSize + 16, y: corner.y + 36}, url)
+	return page
+}
+
+// checklistSection the items of a kind to set up, with the number of each
+type checklistSection struct {
+	title string
+	items []checklistItem
+}
+
+type checklistItem struct {
+	count int
+	name  string
+}
+
+// checklist counts the tiles by landscape, the number tokens by number and the harbors by trade
+func checklist(board *game.Board) []checklistSection {
+	landscapes := make(map[string]int)
+	numbers := make(map[int]int)
+	harbors := make(map[string]int)
+	for _, position := range board.Positions() {
+		tile := board.Tile(position)
+		landscapes[tile.Landscape.Name]++
+		if tile.Number.Pips() > 0 {
+			numbers[tile.Number.Number]++
+		}
+		if tile.Harbor.Name != "" && tile.Harbor != *model.HarborNone {
+			harbors[tile.Harbor.Name]++
+		}
+	}
+
+	sections := []checklistSection{{title: "Tiles"}, {title: "Number tokens"}, {title: "Harbors"}}
+	for name, count := range landscapes {
+		sections[0].items = append(sections[0].items, checklistItem{count: count, name: name})
+	}
+	sort.Slice(sections[0].items, func(i, j int) bool { return sections[0].items[i].name < sections[0].items[j].name })
+	for number := 2; number <= 12; number++ {
+		if numbers[number] > 0 {
+			sections[1].items = append(sections[1].items, checklistItem{count: numbers[number], name: fmt.Sprintf("Number %d", number)})
+		}
+	}
+	for name, count := range harbors {
+		sections[2].items = append(sections[2].items, checklistItem{count: count, name: name})
+	}
+	sort.Slice(sections[2].items, func(i, j int) bool { return sections[2].items[i].name < sections[2].items[j].name })
+	return sections
+}
+
+// checklistPages the pages with a box to tick for every item of the checklist, on as many pages as it takes
+func checklistPages(board *game.Board, gameCode string) []*pdfPage {
+	var pages []*pdfPage
+	var page *pdfPage
+	y := 0.0
+	newPage := func() {
+		page = &pdfPage{}
+		pages = append(pages, page)
+		page.fill(textColour)
+		page.text(fontBold, 20, point{x: pageMargin, y: pageMargin + 20}, "Setup checklist")
+		y = page.wrappedCode(10, point{x: pageMargin, y: pageMargin + 42}, gameCode) + 16
+	}
+	newPage()
+	for _, section := range checklist(board) {
+		if len(section.items) == 0 {
+			continue
+		}
+		if y+40 > pageHeight-pageMargin {
+			newPage()
+		}
+		y += 20
+		page.text(fontBold, 13, point{x: pageMargin, y: y}, section.title)
+		for _, item := range section.items {
+			if y+20 > pageHeight-pageMargin {
+				newPage()
+			}
+			y += 20
+			page.stroke(textColour, 1)
+			page.rectangle(point{x: pageMargin, y: y - 10}, 11, 11)
+			page.paint("S")
+			page.text(fontRegular, 11, point{x: pageMargin + 20, y: y}, fmt.Sprintf("%d x %s", item.count, item.name))
+		}
+		y += 8
+	}
+	return pages
+}
+
+// pdfPage the content stream of a page, the methods take points from the top left of the page, as in the drawing
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+// coordinates turns a point from the top left into the coordinates of PDF, from the bottom left
+func (p *pdfPage) coordinates(at point) string {
+	return fmt.Sprintf("%.2f %.2f", at.x, pageHeight-at.y)
+}
+
+func (p *pdfPage) fill(colour string) {
+	c := rgb(colour)
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f rg\n", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+}
+
+func (p *pdfPage) stroke(colour string, width float64) {
+	c := rgb(colour)
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f RG %.2f w\n", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255, width)
+}
+
+// paint paints the path, f fills it, S strokes it and B does both
+func (p *pdfPage) paint(operator string) {
+	fmt.Fprintln(&p.content, operator)
+}
+
+func (p *pdfPage) path(points []point) {
+	for i, at := range points {
+		operator := "l"
+		if i == 0 {
+			operator = "m"
+		}
+		fmt.Fprintf(&p.content, "%s %s\n", p.coordinates(at), operator)
+	}
+	fmt.Fprintln(&p.content, "h")
+}
+
+// rectangle the path of a rectangle, from its top left corner
+func (p *pdfPage) rectangle(corner point, width float64, height float64) {
+	fmt.Fprintf(&p.content, "%s %.2f %.2f re\n", p.coordinates(point{x: corner.x, y: corner.y + height}), width, height)
+}
+
+// circlePath the path of a circle, as four Bézier curves
+func (p *pdfPage) circlePath(center point, radius float64) {
+	k := radius * 0.5523
+	fmt.Fprintf(&p.content, "%s m\n", p.coordinates(point{x: center.x + radius, y: center.y}))
+	quarters := [][3]point{
+		{{center.x + radius, center.y + k}, {center.x + k, center.y + radius}, {center.x, center.y + radius}},
+		{{center.x - k, center.y + radius}, {center.x - radius, center.y + k}, {center.x - radius, center.y}},
+		{{center.x - radius, center.y - k}, {center.x - k, center.y - radius}, {center.x, center.y - radius}},
+		{{center.x + k, center.y - radius}, {center.x + radius, center.y - k}, {center.x + radius, center.y}},
+	}
+	for _, quarter := range quarters {
+		fmt.Fprintf(&p.content, "%s %s %s c\n", p.coordinates(quarter[0]), p.coordinates(quarter[1]), p.coordinates(quarter[2]))
+	}
+}
+
+// text writes the text from the start of its baseline
+func (p *pdfPage) text(font string, size float64, start point, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %s Td (%s) Tj ET\n", font, size, p.coordinates(start), escapeText(text))
+}
+
+// wrappedCode writes the code in lines that fit on the page, and returns the baseline of the last line
+func (p *pdfPage) wrappedCode(size float64, start point, code string) float64 {
+	perLine := int((pageWidth - pageMargin - start.x) / (size * 0.6))
+	for len(code) > perLine {
+		p.text(fontCode, size, start, code[:perLine])
+		code = code[perLine:]
+		start.y += size * 1.3
+	}
+	p.text(fontCode, size, start, code)
+	return start.y
+}
+
+// escapeText escapes the text for a string of PDF, characters outside of ASCII are replaced by a question mark
+func escapeText(text string) string {
+	var escaped strings.Builder
+	for _, character := range text {
+		switch {
+		case character == '(' || character == ')' || character == '\\':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(character)
+		case character < ' ' || character > '~':
+			escaped.WriteRune('?')
+		default:
+			escaped.WriteRune(character)
+		}
+	}
+	return escaped.String()
+}
+
+// pdfBoard paints a drawing on a page, at the origin and scaled to the size it gets on the page
+type pdfBoard struct {
+	page   *pdfPage
+	origin point
+	scale  float64
+}
+
+func (b *pdfBoard) onPage(at point) point {
+	return point{x: b.origin.x + at.x*b.scale, y: b.origin.y + at.y*b.scale}
+}
+
+func (b *pdfBoard) polygon(points []point, fill string, outline string, width float64) {
+	onPage := make([]point, len(points))
+	for i, at := range points {
+		onPage[i] = b.onPage(at)
+	}
+	b.page.fill(fill)
+	b.page.path(onPage)
+	b.outline(outline, width)
+}
+
+func (b *pdfBoard) circle(center point, radius float64, fill string, outline string, width float64) {
+	b.page.fill(fill)
+	b.page.circlePath(b.onPage(center), radius*b.scale)
+	b.outline(outline, width)
+}
+
+// outline paints the path, filled and stroked when the outline has a width
+func (b *pdfBoard) outline(outline string, width float64) {
+	if width > 0 {
+		b.page.stroke(outline, width*b.scale)
+		b.page.paint("B")
+		return
+	}
+	b.page.paint("f")
+}
+
+func (b *pdfBoard) line(from point, to point, width float64, colour string) {
+	b.page.stroke(colour, width*b.scale)
+	fmt.Fprintf(&b.page.content, "1 J %s m %s l S 0 J\n", b.page.coordinates(b.onPage(from)), b.page.coordinates(b.onPage(to)))
+}
+
+// text writes the numbers and trade ratios in bold, the digits of Helvetica are 0.556 of the font size wide and the colon 0.333
+func (b *pdfBoard) text(text string, center point, size float64, colour string) {
+	size *= b.scale
+	width := 0.0
+	for _, character := range text {
+		if character == ':' {
+			width += size * 0.333
+		} else {
+			width += size * 0.556
+		}
+	}
+	at := b.onPage(center)
+	b.page.fill(colour)
+	b.page.text(fontBold, size, point{x: at.x - width/2, y: at.y + size*0.35}, text)
+}
+
+// writeDocument writes the pages as a PDF document, with compressed content streams
+func writeDocument(w io.Writer, pages []*pdfPage) error {
+	var document bytes.Buffer
+	offsets := make([]int, 0)
+	object := func(body string) {
+		offsets = append(offsets, document.Len())
+		fmt.Fprintf(&document, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// the binary comment tells transfer programs that the document is binary
+	document.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// the catalog, the pages and the fonts come first, every page is followed by its content
+	const firstPage = 6
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	for _, font := range []string{"Helvetica", "Helvetica-Bold", "Courier"} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+	}
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		compressor := zlib.NewWriter(&content)
+		if _, err := compressor.Write(page.content.Bytes()); err != nil {
+			return err
+		}
+		if err := compressor.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /%s 3 0 R /%s 4 0 R /%s 5 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, fontCode, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	references := document.Len()
+	fmt.Fprintf(&document, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&document, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&document, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, references)
+	_, err := w.Write(document.Bytes())
+	return err
+}
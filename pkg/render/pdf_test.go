@@ -0,0 +1,93 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/stretchr/testify/assert"
+)
+
+const codeURL = "https://catan-map-generator.herokuapp.com/api/map/code/" + normalGameCode
+
+// contents returns the uncompressed content streams of the pages of the PDF document
+func contents(t *testing.T, document []byte) []string {
+	streams := regexp.MustCompile(`/Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	pages := make([]string, 0)
+	for _, match := range streams.FindAllSubmatchIndex(document, -1) {
+		length, _ := strconv.Atoi(string(document[match[2]:match[3]]))
+		reader, err := zlib.NewReader(bytes.NewReader(document[match[1] : match[1]+length]))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		pages = append(pages, string(content))
+	}
+	return pages
+}
+
+func TestWritePDF(t *testing.T) {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	var document bytes.Buffer
+	assert.NoError(t, WritePDF(&document, &board, codeURL))
+
+	pdf := document.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "/Count 2")
+	assert.Equal(t, 2, strings.Count(pdf, "/Type /Page "))
+
+	// every object starts at the offset in the cross reference table
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf, -1)
+	assert.Equal(t, 6+2*2-1, len(offsets))
+	for i, offset := range offsets {
+		start, _ := strconv.Atoi(offset[1])
+		assert.True(t, strings.HasPrefix(pdf[start:], strconv.Itoa(i+1)+" 0 obj\n"), offset[1])
+	}
+
+	pages := contents(t, document.Bytes())
+	assert.Equal(t, 2, len(pages))
+	assert.Contains(t, pages[0], "(Normal map) Tj")
+	assert.Contains(t, pages[0], "("+normalGameCode+") Tj")
+	assert.Contains(t, pages[0], "(3:1) Tj")
+	assert.Contains(t, pages[1], "(Setup checklist) Tj")
+	assert.Contains(t, pages[1], "(4 x Forest) Tj")
+	assert.Contains(t, pages[1], "(1 x Number 12) Tj")
+	assert.Contains(t, pages[1], "(4 x 3:1) Tj")
+}
+
+func TestWritePDFWithTooLongURL(t *testing.T) {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	var document bytes.Buffer
+	assert.Error(t, WritePDF(&document, &board, strings.Repeat(codeURL, 10)))
+}
+
+func TestChecklist(t *testing.T) {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	sections := checklist(&board)
+	assert.Equal(t, 3, len(sections))
+
+	expected := []int{board.GameType.TilesCount, 18, board.GameType.HarborCount}
+	for i, section := range sections {
+		total := 0
+		for _, item := range section.items {
+			total += item.count
+		}
+		assert.Equal(t, expected[i], total, section.title)
+	}
+	assert.Equal(t, checklistItem{count: 1, name: "Desert"}, sections[0].items[0])
+	assert.Equal(t, checklistItem{count: 1, name: "Number 2"}, sections[1].items[0])
+}
+
+func TestEscapeText(t *testing.T) {
+	assert.Equal(t, `Seafarers \(New Shores\) \\ ?`, escapeText("Seafarers (New Shores) \\ é"))
+}
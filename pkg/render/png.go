@@ -0,0 +1,205 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/joostvdg/cmg/pkg/game"
+)
+
+// ContentTypePNG the media type of the PNG image of a board
+const ContentTypePNG = "image/png"
+
+// supersampling the number of pixels drawn along each side of a pixel of the image, which smooths the edges of the shapes
+const supersampling = 3
+
+// WritePNG draws the same board as WriteSVG, rasterized as a PNG image
+func WritePNG(w io.Writer, board *game.Board) error {
+	return png.Encode(w, rasterize(layout(board)))
+}
+
+// rasterize paints the shapes of the drawing on an image of its size
+func rasterize(drawing drawing) *image.RGBA {
+	c := newCanvas(drawing.width, drawing.height, supersampling)
+	paint(drawing, c)
+	return c.downsample()
+}
+
+// canvas an image that is painted at a multiple of the size of the drawing, the scale, in points of the drawing
+type canvas struct {
+	image *image.RGBA
+	scale float64
+}
+
+func newCanvas(width float64, height float64, scale int) *canvas {
+	bounds := image.Rect(0, 0, int(math.Ceil(width))*scale, int(math.Ceil(height))*scale)
+	return &canvas{image: image.NewRGBA(bounds), scale: float64(scale)}
+}
+
+// span paints the pixels of a row whose centers are between the two x coordinates
+func (c *canvas) span(y int, fromX float64, toX float64, colour color.RGBA) {
+	from := int(math.Ceil(fromX - 0.5))
+	to := int(math.Floor(toX - 0.5))
+	for x := from; x <= to; x++ {
+		c.image.SetRGBA(x, y, colour)
+	}
+}
+
+// rows returns the rows of pixels whose centers are between the two y coordinates, in pixels of the canvas
+func (c *canvas) rows(fromY float64, toY float64) (int, int) {
+	from := int(math.Max(0, math.Ceil(fromY-0.5)))
+	to := int(math.Min(float64(c.image.Rect.Dy()-1), math.Floor(toY-0.5)))
+	return from, to
+}
+
+func (c *canvas) polygon(points []point, fill string, outline string, width float64) {
+	c.fillPolygon(points, rgb(fill))
+	if width > 0 {
+		for i, p := range points {
+			c.thickLine(p, points[(i+1)%len(points)], width, rgb(outline))
+		}
+	}
+}
+
+func (c *canvas) circle(center point, radius float64, fill string, outline string, width float64) {
+	c.annulus(center, 0, radius, rgb(fill))
+	if width > 0 {
+		c.annulus(center, math.Max(0, radius-width/2), radius+width/2, rgb(outline))
+	}
+}
+
+func (c *canvas) line(from point, to point, width float64, colour string) {
+	c.thickLine(from, to, width, rgb(colour))
+}
+
+// fillPolygon fills the polygon, a row at a time between the edges it crosses
+func (c *canvas) fillPolygon(points []point, colour color.RGBA) {
+	scaled := make([]point, len(points))
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for i, p := range points {
+		scaled[i] = point{x: p.x * c.scale, y: p.y * c.scale}
+		minY, maxY = math.Min(minY, scaled[i].y), math.Max(maxY, scaled[i].y)
+	}
+
+	from, to := c.rows(minY, maxY)
+	crossings := make([]float64, 0, len(scaled))
+	for y := from; y <= to; y++ {
+		center := float64(y) + 0.5
+		crossings = crossings[:0]
+		for i, a := range scaled {
+			b := scaled[(i+1)%len(scaled)]
+			if (a.y <= center && center < b.y) || (b.y <= center && center < a.y) {
+				crossings = append(crossings, a.x+(center-a.y)*(b.x-a.x)/(b.y-a.y))
+			}
+		}
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			c.span(y, crossings[i], crossings[i+1], colour)
+		}
+	}
+}
+
+// annulus paints the pixels between the inner and the outer circle around the center
+func (c *canvas) annulus(center point, inner float64, outer float64, colour color.RGBA) {
+	cx, cy := center.x*c.scale, center.y*c.scale
+	inner, outer = inner*c.scale, outer*c.scale
+
+	from, to := c.rows(cy-outer, cy+outer)
+	for y := from; y <= to; y++ {
+		dy := float64(y) + 0.5 - cy
+		half := math.Sqrt(math.Max(0, outer*outer-dy*dy))
+		if math.Abs(dy) >= inner {
+			c.span(y, cx-half, cx+half, colour)
+			continue
+		}
+		hole := math.Sqrt(inner*inner - dy*dy)
+		c.span(y, cx-half, cx-hole, colour)
+		c.span(y, cx+hole, cx+half, colour)
+	}
+}
+
+// thickLine paints a line of the width between the two points, with round ends
+func (c *canvas) thickLine(from point, to point, width float64, colour color.RGBA) {
+	length := math.Hypot(to.x-from.x, to.y-from.y)
+	if length > 0 {
+		nx, ny := -(to.y-from.y)/length*width/2, (to.x-from.x)/length*width/2
+		c.fillPolygon([]point{
+			{x: from.x + nx, y: from.y + ny},
+			{x: to.x + nx, y: to.y + ny},
+			{x: to.x - nx, y: to.y - ny},
+			{x: from.x - nx, y: from.y - ny},
+		}, colour)
+	}
+	c.annulus(from, 0, width/2, colour)
+	c.annulus(to, 0, width/2, colour)
+}
+
+// text paints the digits and colons of the text in a bitmap font, with the height of the font size
+func (c *canvas) text(text string, center point, size float64, colour string) {
+	cell := size * 0.7 / glyphHeight
+	width := float64(len(text)*(glyphWidth+1)-1) * cell
+	left, top := center.x-width/2, center.y-size*0.35
+	for i, character := range text {
+		glyph, ok := glyphs[character]
+		if !ok {
+			continue
+		}
+		for row, line := range glyph {
+			for column, mark := range line {
+				if mark != '#' {
+					continue
+				}
+				// the dots overlap a little, for a bolder font
+				x := left + (float64(i*(glyphWidth+1)+column)-0.15)*cell
+				y := top + (float64(row)-0.15)*cell
+				dot := cell * 1.3
+				c.fillPolygon([]point{{x, y}, {x + dot, y}, {x + dot, y + dot}, {x, y + dot}}, rgb(colour))
+			}
+		}
+	}
+}
+
+// downsample averages the pixels of the canvas into the pixels of an image of the size of the drawing
+func (c *canvas) downsample() *image.RGBA {
+	scale := int(c.scale)
+	bounds := c.image.Bounds()
+	result := image.NewRGBA(image.Rect(0, 0, bounds.Dx()/scale, bounds.Dy()/scale))
+	samples := uint32(scale * scale)
+	for y := 0; y < result.Rect.Dy(); y++ {
+		for x := 0; x < result.Rect.Dx(); x++ {
+			var r, g, b, a uint32
+			for sy := 0; sy < scale; sy++ {
+				for sx := 0; sx < scale; sx++ {
+					pixel := c.image.RGBAAt(x*scale+sx, y*scale+sy)
+					r, g, b, a = r+uint32(pixel.R), g+uint32(pixel.G), b+uint32(pixel.B), a+uint32(pixel.A)
+				}
+			}
+			result.SetRGBA(x, y, color.RGBA{R: uint8(r / samples), G: uint8(g / samples), B: uint8(b / samples), A: uint8(a / samples)})
+		}
+	}
+	return result
+}
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs the bitmap font of the numbers and trade ratios, each glyph is five dots wide and seven high
+var glyphs = map[rune][glyphHeight]string{
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	':': {".....", "..#..", "..#..", ".....", "..#..", "..#..", "....."},
+}
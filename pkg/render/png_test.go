@@ -0,0 +1,51 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/mapgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePNG(t *testing.T) {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	var encoded bytes.Buffer
+	assert.NoError(t, WritePNG(&encoded, &board))
+
+	image, err := png.Decode(&encoded)
+	assert.NoError(t, err)
+	drawing := layout(&board)
+	assert.Equal(t, int(math.Ceil(drawing.width)), image.Bounds().Dx())
+	assert.Equal(t, int(math.Ceil(drawing.height)), image.Bounds().Dy())
+
+	colourAt := func(at point) [3]uint32 {
+		r, g, b, _ := image.At(int(at.x), int(at.y)).RGBA()
+		return [3]uint32{r >> 8, g >> 8, b >> 8}
+	}
+	colour := func(hex string) [3]uint32 {
+		c := rgb(hex)
+		return [3]uint32{uint32(c.R), uint32(c.G), uint32(c.B)}
+	}
+	assert.Equal(t, colour(backgroundColour), colourAt(point{x: 1, y: 1}))
+	for _, tile := range drawing.tiles {
+		// between the center and a corner, away from the token
+		inside := point{x: (tile.center.x + 3*tile.corners[0].x) / 4, y: tile.center.y}
+		assert.Equal(t, colour(tile.colour), colourAt(inside), tile.position)
+	}
+}
+
+func TestWritePNGForEveryGameType(t *testing.T) {
+	for key, definedGame := range game.DefinedGames {
+		board := mapgen.MapGenerationAttempt(definedGame.GameType, false, rand.New(rand.NewSource(1)))
+		var encoded bytes.Buffer
+		assert.NoError(t, WritePNG(&encoded, &board), key)
+		_, err := png.Decode(&encoded)
+		assert.NoError(t, err, key)
+	}
+}
@@ -0,0 +1,441 @@
+package render
+
+import (
+	"fmt"
+)
+
+// qrVersion the size and error correction blocks of a QR code version, at error correction level M
+// The data of a version is split over blocks, the second group of blocks holds one data codeword more than the first
+type qrVersion struct {
+	version    int
+	ecPerBlock int
+	shortCount int
+	shortData  int
+	longCount  int
+	alignments []int
+}
+
+// qrVersions the versions of QR codes that are encoded, which hold up to 412 bytes, enough for a URL with any game code
+var qrVersions = []qrVersion{
+	{version: 1, ecPerBlock: 10, shortCount: 1, shortData: 16},
+	{version: 2, ecPerBlock: 16, shortCount: 1, shortData: 28, alignments: []int{6, 18}},
+	{version: 3, ecPerBlock: 26, shortCount: 1, shortData: 44, alignments: []int{6, 22}},
+	{version: 4, ecPerBlock: 18, shortCount: 2, shortData: 32, alignments: []int{6, 26}},
+	{version: 5, ecPerBlock: 24, shortCount: 2, shortData: 43, alignments: []int{6, 30}},
+	{version: 6, ecPerBlock: 16, shortCount: 4, shortData: 27, alignments: []int{6, 34}},
+	{version: 7, ecPerBlock: 18, shortCount: 4, shortData: 31, alignments: []int{6, 22, 38}},
+	{version: 8, ecPerBlock: 22, shortCount: 2, shortData: 38, longCount: 2, alignments: []int{6, 24, 42}},
+	{version: 9, ecPerBlock: 22, shortCount: 3, shortData: 36, longCount: 2, alignments: []int{6, 26, 46}},
+	{version: 10, ecPerBlock: 26, shortCount: 4, shortData: 43, longCount: 1, alignments: []int{6, 28, 50}},
+	{version: 11, ecPerBlock: 30, shortCount: 1, shortData: 50, longCount: 4, alignments: []int{6, 30, 54}},
+	{version: 12, ecPerBlock: 22, shortCount: 6, shortData: 36, longCount: 2, alignments: []int{6, 32, 58}},
+	{version: 13, ecPerBlock: 22, shortCount: 8, shortData: 37, longCount: 1, alignments: []int{6, 34, 62}},
+	{version: 14, ecPerBlock: 24, shortCount: 4, shortData: 40, longCount: 5, alignments: []int{6, 26, 46, 66}},
+	{version: 15, ecPerBlock: 24, shortCount: 5, shortData: 41, longCount: 5, alignments: []int{6, 26, 48, 70}},
+}
+
+// dataCodewords the number of codewords of the version that hold data
+func (v qrVersion) dataCodewords() int {
+	return v.shortCount*v.shortData + v.longCount*(v.shortData+1)
+}
+
+// size the number of modules along a side of the symbol
+func (v qrVersion) size() int {
+	return v.version*4 + 17
+}
+
+// countBits the length of the character count of the byte mode
+func (v qrVersion) countBits() int {
+	if v.version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// qrCode the modules of a QR code, by row and column, true for a dark module
+type qrCode [][]bool
+
+// encodeQR encodes the text as a QR code in byte mode with error correction level M,
+// in the smallest version it fits in and with the mask that results in the lowest penalty
+func encodeQR(text string) (qrCode, error) {
+	var version qrVersion
+	found := false
+	for _, candidate := range qrVersions {
+		if 4+candidate.countBits()+len(text)*8 <= candidate.dataCodewords()*8 {
+			version, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("text of %d bytes is too long for a QR code", len(text))
+	}
+
+	symbol := newQRSymbol(version)
+	symbol.placeCodewords(interleave(version, qrData(version, text)))
+	best, lowest := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		symbol.applyMask(mask)
+		symbol.drawFormat(mask)
+		if penalty := symbol.penalty(); lowest < 0 || penalty < lowest {
+			best, lowest = mask, penalty
+		}
+		symbol.applyMask(mask)
+	}
+	symbol.applyMask(best)
+	symbol.drawFormat(best)
+	return symbol.modules, nil
+}
+
+// qrData the data codewords of the text: the byte mode, the length, the bytes, a terminator and the padding
+func qrData(version qrVersion, text string) []byte {
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(text), version.countBits())
+	for i := 0; i < len(text); i++ {
+		bits.append(int(text[i]), 8)
+	}
+	capacity := version.dataCodewords() * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+
+	data := make([]byte, 0, version.dataCodewords())
+	for i := 0; i < len(bits); i += 8 {
+		codeword := byte(0)
+		for _, bit := range bits[i : i+8] {
+			codeword <<= 1
+			if bit {
+				codeword |= 1
+			}
+		}
+		data = append(data, codeword)
+	}
+	for pad := byte(0xec); len(data) < cap(data); pad ^= 0xec ^ 0x11 {
+		data = append(data, pad)
+	}
+	return data
+}
+
+// bitBuffer the bits of the data, most significant first
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+// interleave splits the data over the blocks of the version, adds the error correction to each block,
+// and takes a codeword from each block in turn, the data of all blocks before their error correction
+func interleave(version qrVersion, data []byte) []byte {
+	divisor := reedSolomonDivisor(version.ecPerBlock)
+	blocks := make([][]byte, 0, version.shortCount+version.longCount)
+	corrections := make([][]byte, 0, cap(blocks))
+	for i := 0; i < cap(blocks); i++ {
+		length := version.shortData
+		if i >= version.shortCount {
+			length++
+		}
+		blocks = append(blocks, data[:length])
+		corrections = append(corrections, reedSolomonRemainder(data[:length], divisor))
+		data = data[length:]
+	}
+
+	result := make([]byte, 0, version.dataCodewords()+version.ecPerBlock*len(blocks))
+	for i := 0; i <= version.shortData; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < version.ecPerBlock; i++ {
+		for _, correction := range corrections {
+			result = append(result, correction[i])
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor the generator polynomial of the degree, without its leading term, highest power first
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder the error correction codewords of the data, the remainder of its division by the divisor
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, codeword := range data {
+		factor := codeword ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in the Galois field of 256 elements of QR codes
+func gfMultiply(x byte, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrSymbol a QR code while it is being drawn, functions marks the modules of the patterns that are not data
+type qrSymbol struct {
+	version   qrVersion
+	modules   qrCode
+	functions [][]bool
+}
+
+// newQRSymbol draws the finder, timing and alignment patterns and the version information of the version
+func newQRSymbol(version qrVersion) *qrSymbol {
+	size := version.size()
+	symbol := &qrSymbol{version: version, modules: make(qrCode, size), functions: make([][]bool, size)}
+	for row := range symbol.modules {
+		symbol.modules[row] = make([]bool, size)
+		symbol.functions[row] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		symbol.set(6, i, i%2 == 0)
+		symbol.set(i, 6, i%2 == 0)
+	}
+	for _, corner := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || x >= size || y < 0 || y >= size {
+					continue
+				}
+				distance := max(abs(dx), abs(dy))
+				symbol.set(x, y, distance != 2 && distance != 4)
+			}
+		}
+	}
+	last := len(version.alignments) - 1
+	for i, x := range version.alignments {
+		for j, y := range version.alignments {
+			// the alignment patterns that would overlap the finder patterns are left out
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					symbol.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// reserve the format information, which depends on the mask
+	symbol.drawFormat(0)
+	if version.version >= 7 {
+		remainder := version.version
+		for i := 0; i < 12; i++ {
+			remainder = remainder<<1 ^ (remainder>>11)*0x1f25
+		}
+		bits := version.version<<12 | remainder
+		for i := 0; i < 18; i++ {
+			a, b := size-11+i%3, i/3
+			symbol.set(a, b, bits>>i&1 == 1)
+			symbol.set(b, a, bits>>i&1 == 1)
+		}
+	}
+	return symbol
+}
+
+// set sets the module at column x and row y as part of a pattern
+func (s *qrSymbol) set(x int, y int, dark bool) {
+	s.modules[y][x] = dark
+	s.functions[y][x] = true
+}
+
+// drawFormat draws both copies of the format information, the error correction level M and the mask
+func (s *qrSymbol) drawFormat(mask int) {
+	data := mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = remainder<<1 ^ (remainder>>9)*0x537
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	size := len(s.modules)
+	for i := 0; i <= 5; i++ {
+		s.set(8, i, bit(i))
+	}
+	s.set(8, 7, bit(6))
+	s.set(8, 8, bit(7))
+	s.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		s.set(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.set(8, size-15+i, bit(i))
+	}
+	s.set(8, size-8, true)
+}
+
+// placeCodewords places the bits of the codewords in the zigzag of columns of two modules, from the bottom right,
+// skipping the modules of the patterns
+func (s *qrSymbol) placeCodewords(codewords []byte) {
+	size := len(s.modules)
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vertical := 0; vertical < size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vertical
+				if (right+1)&2 == 0 {
+					y = size - 1 - vertical
+				}
+				if s.functions[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				s.modules[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules the mask pattern selects, applying it twice undoes it
+func (s *qrSymbol) applyMask(mask int) {
+	for y, row := range s.modules {
+		for x := range row {
+			if s.functions[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			row[x] = row[x] != invert
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to read: runs and blocks of the same colour,
+// patterns that look like a finder pattern and an imbalance of dark and light modules
+func (s *qrSymbol) penalty() int {
+	size := len(s.modules)
+	penalty := 0
+	dark := 0
+	for i := 0; i < size; i++ {
+		row := make([]bool, size)
+		column := make([]bool, size)
+		for j := 0; j < size; j++ {
+			row[j] = s.modules[i][j]
+			column[j] = s.modules[j][i]
+			if row[j] {
+				dark++
+			}
+		}
+		penalty += linePenalty(row) + linePenalty(column)
+	}
+	for y := 0; y < size-1; y++ {
+		for x := 0; x < size-1; x++ {
+			colour := s.modules[y][x]
+			if s.modules[y][x+1] == colour && s.modules[y+1][x] == colour && s.modules[y+1][x+1] == colour {
+				penalty += 3
+			}
+		}
+	}
+	// ten points for every five percent the dark modules are off from half of them
+	total := size * size
+	penalty += (abs(dark*20-total*10)+total-1)/total*10 - 10
+	return penalty
+}
+
+// linePenalty scores the runs of five or more modules of the same colour, and the patterns that look like a finder pattern:
+// dark, light, dark, light and dark runs in the ratio 1:1:3:1:1, with four times as much light on one side and some on the other,
+// where the quiet zone around the symbol counts as light
+func linePenalty(line []bool) int {
+	penalty := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += run - 2
+		}
+		run = 1
+	}
+
+	// the runs alternate between light and dark, the first and the last are light and include the quiet zone
+	runs := []int{len(line)}
+	for _, dark := range line {
+		if dark == (len(runs)%2 == 1) {
+			runs = append(runs, 0)
+		}
+		runs[len(runs)-1]++
+	}
+	if len(runs)%2 == 0 {
+		runs = append(runs, 0)
+	}
+	runs[len(runs)-1] += len(line)
+	for i := 1; i+5 < len(runs); i += 2 {
+		n := runs[i]
+		if runs[i+1] != n || runs[i+2] != 3*n || runs[i+3] != n || runs[i+4] != n {
+			continue
+		}
+		if runs[i-1] >= 4*n && runs[i+5] >= n {
+			penalty += 40
+		}
+		if runs[i+5] >= 4*n && runs[i-1] >= n {
+			penalty += 40
+		}
+	}
+	return penalty
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,177 @@
+package render
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// the data and error correction codewords of HELLO WORLD in a version 1 QR code at level M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	assert.Equal(t, expected, reedSolomonRemainder(data, reedSolomonDivisor(10)))
+}
+
+func TestEncodeQR(t *testing.T) {
+	texts := map[string]int{
+		"a": 1,
+		"https://catan-map-generator.herokuapp.com/api/map/code/" + normalGameCode: 7,
+		strings.Repeat("x", 200): 10,
+		strings.Repeat("x", 412): 15,
+	}
+	for text, version := range texts {
+		code, err := encodeQR(text)
+		assert.NoError(t, err)
+		assert.Equal(t, version*4+17, len(code), text)
+		assert.Equal(t, text, decodeQR(t, code))
+	}
+
+	_, err := encodeQR(strings.Repeat("x", 413))
+	assert.Error(t, err)
+}
+
+func TestEncodeQRFinderPatterns(t *testing.T) {
+	code, err := encodeQR(normalGameCode)
+	assert.NoError(t, err)
+	size := len(code)
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for row := 0; row < 7; row++ {
+			for column := 0; column < 7; column++ {
+				ring := max(abs(row-3), abs(column-3))
+				assert.Equal(t, ring != 2, code[corner[1]+row][corner[0]+column])
+			}
+		}
+	}
+}
+
+func TestEncodeQRGolden(t *testing.T) {
+	// reference symbols of versions 1, 7 and 10 at level M, each file the text and then a row of modules per line,
+	// generated apart from this encoder from the tables of ISO/IEC 18004, and checked against its error correction example
+	for _, file := range []string{"testdata/qr_v1.txt", "testdata/qr_v7.txt", "testdata/qr_v10.txt"} {
+		golden, err := os.ReadFile(file)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(string(golden)), "\n")
+		code, err := encodeQR(lines[0])
+		if !assert.NoError(t, err, file) {
+			continue
+		}
+		rows := make([]string, 0, len(code))
+		for _, row := range code {
+			var modules strings.Builder
+			for _, dark := range row {
+				if dark {
+					modules.WriteByte('#')
+				} else {
+					modules.WriteByte('.')
+				}
+			}
+			rows = append(rows, modules.String())
+		}
+		assert.Equal(t, lines[1:], rows, file)
+	}
+}
+
+// decodeQR reads the text back from the code: the mask from the format information, the codewords from the zigzag,
+// and the data from the blocks after checking their error correction
+func decodeQR(t *testing.T, code qrCode) string {
+	var version qrVersion
+	for _, candidate := range qrVersions {
+		if candidate.size() == len(code) {
+			version = candidate
+		}
+	}
+	size := len(code)
+
+	format := 0
+	for i := 0; i < 8; i++ {
+		if code[8][size-1-i] {
+			format |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if code[size-15+i][8] {
+			format |= 1 << i
+		}
+	}
+	format = (format ^ 0x5412) >> 10
+	assert.Equal(t, 0, format>>3, "error correction level M")
+
+	symbol := newQRSymbol(version)
+	symbol.modules = code
+	symbol.applyMask(format & 7)
+	defer symbol.applyMask(format & 7)
+
+	var codewords []byte
+	bits := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vertical := 0; vertical < size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vertical
+				if (right+1)&2 == 0 {
+					y = size - 1 - vertical
+				}
+				if symbol.functions[y][x] {
+					continue
+				}
+				if bits%8 == 0 {
+					codewords = append(codewords, 0)
+				}
+				if code[y][x] {
+					codewords[bits/8] |= 1 << (7 - bits%8)
+				}
+				bits++
+			}
+		}
+	}
+
+	count := version.shortCount + version.longCount
+	blocks := make([][]byte, count)
+	corrections := make([][]byte, count)
+	for i := 0; i <= version.shortData; i++ {
+		for block := 0; block < count; block++ {
+			if i < version.shortData || block >= version.shortCount {
+				blocks[block] = append(blocks[block], codewords[0])
+				codewords = codewords[1:]
+			}
+		}
+	}
+	for i := 0; i < version.ecPerBlock; i++ {
+		for block := 0; block < count; block++ {
+			corrections[block] = append(corrections[block], codewords[0])
+			codewords = codewords[1:]
+		}
+	}
+	var data bitBuffer
+	for block := 0; block < count; block++ {
+		assert.Equal(t, corrections[block], reedSolomonRemainder(blocks[block], reedSolomonDivisor(version.ecPerBlock)))
+		for _, codeword := range blocks[block] {
+			data.append(int(codeword), 8)
+		}
+	}
+
+	read := func(length int) int {
+		value := 0
+		for _, bit := range data[:length] {
+			value <<= 1
+			if bit {
+				value |= 1
+			}
+		}
+		data = data[length:]
+		return value
+	}
+	assert.Equal(t, 0x4, read(4), "byte mode")
+	text := make([]byte, read(version.countBits()))
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return string(text)
+}
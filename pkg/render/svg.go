@@ -0,0 +1,63 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/game"
+)
+
+// ContentTypeSVG the media type of the SVG image of a board
+const ContentTypeSVG = "image/svg+xml"
+
+// WriteSVG draws the board as an SVG image, with a hex for every tile coloured by its landscape,
+// a token with the number and its pips on every tile that produces, with the 6 and 8 in red,
+// and a marker with the trade ratio for every harbor, on the coast it faces
+func WriteSVG(w io.Writer, board *game.Board) error {
+	drawing := layout(board)
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n",
+		drawing.width, drawing.height, drawing.width, drawing.height)
+	fmt.Fprintf(writer, "<title>%s %s</title>\n", html.EscapeString(board.GameType.Name), html.EscapeString(board.GetGameCode(false)))
+	fmt.Fprintf(writer, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", backgroundColour)
+
+	for _, harbor := range drawing.harbors {
+		for _, dock := range harbor.docks {
+			fmt.Fprintf(writer, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f"/>`+"\n",
+				harbor.center.x, harbor.center.y, dock.x, dock.y, dockColour, hexSize/12)
+		}
+	}
+
+	for _, tile := range drawing.tiles {
+		points := make([]string, 0, len(tile.corners))
+		for _, corner := range tile.corners {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", corner.x, corner.y))
+		}
+		fmt.Fprintf(writer, `<polygon points="%s" fill="%s" stroke="%s" stroke-width="2"><title>%s %s</title></polygon>`+"\n",
+			strings.Join(points, " "), tile.colour, outlineColour, tile.position, html.EscapeString(tile.landscape))
+		if tile.number == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s"/>`+"\n",
+			tile.center.x, tile.center.y, tokenRadius, tokenColour, outlineColour)
+		fmt.Fprintf(writer, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.0f" font-weight="bold" text-anchor="middle" fill="%s">%d</text>`+"\n",
+			tile.center.x, tile.center.y+numberSize/3, numberSize, tile.textColour, tile.number)
+		for _, pip := range tile.pips {
+			fmt.Fprintf(writer, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`+"\n", pip.x, pip.y, pipRadius, tile.textColour)
+		}
+	}
+
+	for _, harbor := range drawing.harbors {
+		fmt.Fprintf(writer, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s" stroke-width="2"><title>%s</title></circle>`+"\n",
+			harbor.center.x, harbor.center.y, harborRadius, harbor.colour, outlineColour, html.EscapeString(harbor.name))
+		fmt.Fprintf(writer, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.0f" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			harbor.center.x, harbor.center.y+ratioSize/3, ratioSize, textColour, harbor.ratio)
+	}
+
+	fmt.Fprintln(writer, "</svg>")
+	return writer.Flush()
+}
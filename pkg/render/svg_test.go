@@ -0,0 +1,86 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/mapgen"
+	"github.com/stretchr/testify/assert"
+)
+
+const normalGameCode = "2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0"
+
+func drawCode(t *testing.T, code string) string {
+	board, err := game.InflateGameFromCode(code)
+	assert.NoError(t, err)
+	var image bytes.Buffer
+	assert.NoError(t, WriteSVG(&image, &board))
+	return image.String()
+}
+
+// elements counts the elements of the SVG by their name, and fails when the SVG is not well-formed
+func elements(t *testing.T, svg string) map[string]int {
+	counts := make(map[string]int)
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			counts[start.Name.Local]++
+		}
+	}
+	return counts
+}
+
+func TestWriteSVG(t *testing.T) {
+	svg := drawCode(t, normalGameCode)
+	counts := elements(t, svg)
+
+	assert.Equal(t, 19, counts["polygon"])
+	// two docks for each of the nine harbors
+	assert.Equal(t, 18, counts["line"])
+	// a number on the 18 tiles that produce, and a ratio on the nine harbors
+	assert.Equal(t, 27, counts["text"])
+	// a token and its pips for the 18 numbers, and a marker for the nine harbors
+	assert.Equal(t, 18+58+9, counts["circle"])
+	assert.Contains(t, svg, "<title>Normal "+normalGameCode+"</title>")
+	assert.Contains(t, svg, ">3:1</text>")
+	assert.Contains(t, svg, ">2:1</text>")
+	assert.Contains(t, svg, `fill="`+redColour+`">6</text>`)
+	assert.Contains(t, svg, `fill="`+redColour+`">8</text>`)
+}
+
+func TestWriteSVGForEveryGameType(t *testing.T) {
+	for key, definedGame := range game.DefinedGames {
+		board := mapgen.MapGenerationAttempt(definedGame.GameType, false, rand.New(rand.NewSource(1)))
+		var image bytes.Buffer
+		assert.NoError(t, WriteSVG(&image, &board), key)
+		assert.Equal(t, definedGame.GameType.TilesCount, elements(t, image.String())["polygon"], key)
+	}
+}
+
+func TestLayoutFitsTheImage(t *testing.T) {
+	board, err := game.InflateGameFromCode(normalGameCode)
+	assert.NoError(t, err)
+	drawing := layout(&board)
+	for _, tile := range drawing.tiles {
+		for _, corner := range tile.corners {
+			assert.True(t, corner.x >= 0 && corner.x <= drawing.width, tile.position)
+			assert.True(t, corner.y >= 0 && corner.y <= drawing.height, tile.position)
+		}
+	}
+	for _, harbor := range drawing.harbors {
+		assert.True(t, harbor.center.x-harborRadius >= 0 && harbor.center.x+harborRadius <= drawing.width, harbor.name)
+		assert.True(t, harbor.center.y-harborRadius >= 0 && harbor.center.y+harborRadius <= drawing.height, harbor.name)
+	}
+}
@@ -0,0 +1,30 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// testGameCode a valid code of a Normal game, for the tests of the endpoints that take a game code
+const testGameCode = "2j35f04a02e61b64c61h23i45d63f63g61h62d65g63b61e56z04c12i0"
+
+// callByCode calls the handler with the game code as the code parameter of a request on the path,
+// and unmarshals the JSON body of the response into the content, returns the status of the response
+func callByCode(t *testing.T, handler echo.HandlerFunc, path string, code string, content interface{}) int {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues(code)
+
+	if assert.NoError(t, handler(c)) {
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), content))
+	}
+	return rec.Code
+}
@@ -0,0 +1,59 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConvertMapCode converts a game code into the format of the format parameter, v1 or v2
+// Without a valid format, a v1 code is converted into a v2 code and the other way around
+func ConvertMapCode(c echo.Context) error {
+	code := c.Param("code")
+	requestInfo := GetRequestInfoFromRequest(c)
+
+	content := model.ConvertedCode{Code: sanitize.Name(code), Format: string(game.GameCodeFormat(code))}
+	format, ok := game.ParseCodeFormat(c.QueryParam("format"))
+	if !ok {
+		format = game.CodeFormatV2
+		if game.GameCodeFormat(code) == game.CodeFormatV2 {
+			format = game.CodeFormatV1
+		}
+	}
+	content.ConvertedFormat = string(format)
+
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(c, code, err, requestInfo.JSONP, requestInfo.Callback, func(message string) interface{} {
+			content.Error = message
+			return &content
+		})
+	}
+	// the code as given, without delimiters and in capitals for a v2 code
+	content.GameType = board.GameType.Name
+	content.Code, _ = board.GetGameCodeInFormat(game.CodeFormat(content.Format))
+	content.ConvertedCode, err = board.GetGameCodeInFormat(format)
+	if err != nil {
+		content.Error = fmt.Sprintf("Could not convert game code %s to %s, reason: %v", sanitize.Name(code), format, err)
+		log.Warn(content.Error)
+		if requestInfo.JSONP {
+			return c.JSONP(http.StatusBadRequest, requestInfo.Callback, &content)
+		}
+		return c.JSON(http.StatusBadRequest, &content)
+	}
+
+	log.WithFields(log.Fields{
+		"RequestId": requestInfo.RequestId,
+		"Code":      sanitize.Name(code),
+		"Format":    format,
+	}).Info("Converted a game code")
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &content)
+	}
+	return c.JSON(http.StatusOK, &content)
+}
@@ -0,0 +1,55 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func convertCode(t *testing.T, code string, query string) (int, model.ConvertedCode) {
+	targetPath := fmt.Sprintf("%v/map/code/%v/convert?%v", baseApiPath, code, query)
+
+	var converted model.ConvertedCode
+	status := callByCode(t, ConvertMapCode, targetPath, code, &converted)
+	return status, converted
+}
+
+func TestConvertMapCode(t *testing.T) {
+	code := testGameCode
+	codeV2 := "N2-6KHGC85E0PCY22ACH69P9Y2656HY8P1DM0CS685WJB"
+
+	status, converted := convertCode(t, code, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, converted.Error)
+	assert.Equal(t, game.NormalGame.Name, converted.GameType)
+	assert.Equal(t, "v1", converted.Format)
+	assert.Equal(t, "v2", converted.ConvertedFormat)
+	assert.Equal(t, codeV2, converted.ConvertedCode)
+
+	status, converted = convertCode(t, codeV2, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "v2", converted.Format)
+	assert.Equal(t, code, converted.ConvertedCode)
+
+	status, converted = convertCode(t, strings.ToLower(codeV2), "format=v2")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, codeV2, converted.Code)
+	assert.Equal(t, codeV2, converted.ConvertedCode)
+}
+
+func TestConvertMapCodeWithTypo(t *testing.T) {
+	status, converted := convertCode(t, "N2-6KHGC85E0PCY22ACH69P9Y2656HY8P1DM0CS685WJC", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code n2-6khgc85e0pcy22ach69p9y2656hy8p1dm0cs685wjc, reason: Invalid checksum, the code contains a typo", converted.Error)
+}
+
+func TestConvertMapCodeIsUnrecognizable(t *testing.T) {
+	status, converted := convertCode(t, "abc", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", converted.Error)
+}
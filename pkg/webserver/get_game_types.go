@@ -0,0 +1,36 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetGameTypes lists the game types of the registry, including those loaded from game definitions,
+// the bundled game types first
+// The rules of a game type have its key as the GameTypeString, so they can be posted as the rules of a map request as they are
+func GetGameTypes(c echo.Context) error {
+	requestInfo := GetRequestInfoFromRequest(c)
+
+	keys := game.DefinedGames.Keys()
+	content := make([]model.GameTypeListing, 0, len(keys))
+	for _, key := range keys {
+		definedGame := game.DefinedGames[key]
+		rules := definedGame.Rules
+		rules.GameTypeString = key
+		content = append(content, model.GameTypeListing{
+			Key:        key,
+			Name:       definedGame.GameType.Name,
+			CodeTag:    definedGame.GameType.CodeTag,
+			TilesCount: definedGame.GameType.TilesCount,
+			Rules:      rules,
+		})
+	}
+
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &content)
+	}
+	return c.JSON(http.StatusOK, &content)
+}
@@ -0,0 +1,56 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGameTypes(t *testing.T) {
+	targetPath := fmt.Sprintf("%v/gametypes", baseApiPath)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(t, GetGameTypes(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	var gameTypes []model.GameTypeListing
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameTypes)) && assert.Len(t, gameTypes, len(game.DefinedGames)) {
+		keys := make([]string, len(gameTypes))
+		for i, gameType := range gameTypes {
+			keys[i] = gameType.Key
+			assert.Equal(t, gameType.Key, gameType.Rules.GameTypeString)
+		}
+		assert.Equal(t, []string{"normal", "large", "seafarers-new-shores", "seafarers-four-islands"}, keys)
+		assert.Equal(t, "Large", gameTypes[1].Name)
+		assert.Equal(t, "L", gameTypes[1].CodeTag)
+		assert.Equal(t, 30, gameTypes[1].TilesCount)
+		assert.Equal(t, game.DefaultGameRulesLarge.Players, gameTypes[1].Rules.Players)
+	}
+}
+
+func TestGetGameTypesSupportsJsonP(t *testing.T) {
+	callback := "MyFunction"
+	targetPath := fmt.Sprintf("%v/gametypes?jsonp=true&callback=%v", baseApiPath, callback)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(t, GetGameTypes(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.True(t, strings.HasPrefix(rec.Body.String(), callback+"("))
+	assert.True(t, strings.HasSuffix(rec.Body.String(), ");"))
+}
@@ -12,10 +12,19 @@ func GetMap(c echo.Context) error {
 
 	requestInfo := GetRequestInfoFromRequest(c)
 	rules := GetGameRulesFromRequest(c)
+	options, err := GetGenerationOptionsFromRequest(c)
+	if err != nil {
+		return InvalidParameters(c, err, rules, requestInfo)
+	}
+	locks, err := GetLocksFromRequest(c, rules, options)
+	if err != nil {
+		return InvalidLocks(c, err, rules, requestInfo)
+	}
+	options.Locks = locks
 
-	gameMap, err := mapgen.ProcessMapGenerationRequest(rules, requestInfo)
+	gameMap, err := mapgen.ProcessMapGenerationRequest(c.Request().Context(), rules, options, requestInfo)
 	if err != nil {
-		return AbortingMapGeneration(c, rules, requestInfo)
+		return FailedMapGeneration(c, err, rules, options, requestInfo)
 	}
 
 	if requestInfo.JSONP {
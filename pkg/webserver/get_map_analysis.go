@@ -0,0 +1,40 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/analysis"
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetMapAnalysis analyzes the board of a game code, and reports how fair it is
+// Such as the expected production per resource, how the scores of the intersections are distributed,
+// how close the red numbers are, how useful the harbors are, and how central the desert is
+func GetMapAnalysis(c echo.Context) error {
+	code := c.Param("code")
+	requestInfo := GetRequestInfoFromRequest(c)
+
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(c, code, err, requestInfo.JSONP, requestInfo.Callback, func(message string) interface{} {
+			content := model.Analysis{Error: message}
+			content.GameCode = sanitize.Name(code)
+			return &content
+		})
+	}
+
+	content := model.Analysis{Analysis: analysis.Analyze(&board)}
+	log.WithFields(log.Fields{
+		"RequestId": requestInfo.RequestId,
+		"Code":      sanitize.Name(code),
+	}).Info("Analyzed a map")
+
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &content)
+	}
+	return c.JSON(http.StatusOK, &content)
+}
@@ -0,0 +1,48 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func analyzeCode(t *testing.T, code string) (int, model.Analysis) {
+	targetPath := fmt.Sprintf("%v/map/code/%v/analysis", baseApiPath, code)
+
+	var analysis model.Analysis
+	status := callByCode(t, GetMapAnalysis, targetPath, code, &analysis)
+	return status, analysis
+}
+
+func TestGetMapAnalysis(t *testing.T) {
+	code := testGameCode
+	status, analysis := analyzeCode(t, code)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, analysis.Error)
+	assert.Equal(t, game.NormalGame.Name, analysis.GameType)
+	assert.Equal(t, code, analysis.GameCode)
+	assert.Len(t, analysis.Resources, 5)
+	assert.Equal(t, 54, analysis.Intersections.Count)
+	assert.Len(t, analysis.Harbors, 9)
+	assert.Equal(t, []string{"e0"}, analysis.Desert.Positions)
+}
+
+func TestGetMapAnalysisIsUnrecognizable(t *testing.T) {
+	status, analysis := analyzeCode(t, "abc")
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", analysis.Error)
+	assert.Empty(t, analysis.Resources)
+}
+
+func TestGetMapAnalysisWithTypo(t *testing.T) {
+	status, analysis := analyzeCode(t, "N2-6KHGC85E0PCY22ACH69P9Y2656HY8P1DM0CS685WJC")
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code n2-6khgc85e0pcy22ach69p9y2656hy8p1dm0cs685wjc, reason: Invalid checksum, the code contains a typo", analysis.Error)
+}
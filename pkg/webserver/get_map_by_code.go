@@ -1,6 +1,7 @@
 package webserver
 
 import (
+	"errors"
 	"fmt"
 	"github.com/getsentry/sentry-go"
 	sentryecho "github.com/getsentry/sentry-go/echo"
@@ -13,13 +14,23 @@ import (
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/segmentio/analytics-go.v3"
 	"net/http"
+	"strings"
 	"time"
 )
 
 // GetMap starts the Generation Cycle, which may or may not succeed with a valid map according to the supplied Game Rules
+// A code with the .svg or .png suffix returns the map as an image instead, with the .pdf suffix as a printable table sheet
 func GetMapByCode(ctx echo.Context) error {
-	cmgContext := ctx.(*context.CMGContext)
 	code := ctx.Param("code")
+	switch {
+	case strings.HasSuffix(code, svgSuffix):
+		return GetMapSVGByCode(ctx)
+	case strings.HasSuffix(code, pngSuffix):
+		return GetMapPNGByCode(ctx)
+	case strings.HasSuffix(code, pdfSuffix):
+		return GetMapPDFByCode(ctx)
+	}
+	cmgContext := ctx.(*context.CMGContext)
 	callback := ctx.QueryParam("callback")
 	jsonp := ctx.QueryParam("jsonp")
 	requestUuid, _ := uuid.NewUUID()
@@ -33,42 +44,23 @@ func GetMapByCode(ctx echo.Context) error {
 		"RemoteAddr": ctx.Request().RemoteAddr,
 	}).Info("Attempt to inflate map from a code:")
 
-	gameType := game.NormalGame
-
-	// TODO: have a map with game types
-	delimiter := false
-	var board = game.Board{}
-	switch len(code) {
-	case 62:
-		delimiter = true
-		fallthrough
-	case 57:
-		// normal game
-		gameType = game.NormalGame
-		inflatedBoard, err := game.InflateNormalGameFromCode(code, &gameType)
-		if err != nil {
-			return invalidGameCode(ctx, "Invalid code value", sanitize.Name(code), jsonp, callback)
-		}
-		board = inflatedBoard
-	case 97:
-		delimiter = true
-		fallthrough
-	case 90:
-		// large game
-		gameType = game.LargeGame
-		inflatedBoard, err := game.InflateLargeGameFromCode(code, &gameType)
-		if err != nil {
-			return invalidGameCode(ctx, "Invalid code value", sanitize.Name(code), jsonp, callback)
-		}
-		board = inflatedBoard
-	default:
-		return invalidGameCode(ctx, "Unrecognizable game code", sanitize.Name(code), jsonp, callback)
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(ctx, code, err, jsonp == "true", callback, func(message string) interface{} {
+			return &model.Map{GameCode: sanitize.Name(code), Error: message}
+		})
 	}
+	gameType := board.GameType
+	delimiter := strings.Contains(code, game.DefaultGameRulesNormal.Delimiter)
 
+	intersections, roads := board.Graph()
 	var content = model.Map{
-		GameType: gameType.Name,
-		Board:    board.Board,
-		GameCode: board.GetGameCode(delimiter),
+		GameType:         gameType.Name,
+		Board:            board.Board,
+		HarborDirections: board.HarborDirections,
+		GameCode:         board.GetGameCode(delimiter),
+		Intersections:    intersections,
+		Roads:            roads,
 	}
 
 	t := time.Now()
@@ -102,11 +94,13 @@ func GetMapByCode(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, &content)
 }
 
-func invalidGameCode(ctx echo.Context, reason string, code string, jsonp string, callback string) error {
-	message := fmt.Sprintf("Could not inflate map base on game code %s, reason: %s", sanitize.Name(code), reason)
+// respondInvalidGameCode responds with a bad request to a game code that could not be inflated, because of the error
+// The body of the response is the content of the endpoint, which gets the message with the reason
+func respondInvalidGameCode(ctx echo.Context, code string, err error, jsonp bool, callback string, content func(message string) interface{}) error {
+	message := fmt.Sprintf("Could not inflate map base on game code %s, reason: %s", sanitize.Name(code), invalidGameCodeReason(err))
 	if hub := sentryecho.GetHubFromContext(ctx); hub != nil {
 		hub.WithScope(func(scope *sentry.Scope) {
-			scope.SetExtra("Code", code)
+			scope.SetExtra("Code", sanitize.Name(code))
 			scope.SetExtra("RequestURI", ctx.Request().RequestURI)
 			hub.CaptureMessage(message)
 			hub.Flush(time.Second * 5)
@@ -114,12 +108,18 @@ func invalidGameCode(ctx echo.Context, reason string, code string, jsonp string,
 	}
 
 	log.Warn(message)
-	var content = model.Map{
-		GameCode: code,
-		Error:    message,
+	if jsonp {
+		return ctx.JSONP(http.StatusBadRequest, callback, content(message))
 	}
-	if jsonp == "true" {
-		return ctx.JSONP(http.StatusBadRequest, callback, &content)
+	return ctx.JSON(http.StatusBadRequest, content(message))
+}
+
+// invalidGameCodeReason returns why a game code could not be inflated, for the error that inflating it returned
+func invalidGameCodeReason(err error) string {
+	if errors.Is(err, game.ErrUnrecognizableCode) {
+		return "Unrecognizable game code"
+	} else if errors.Is(err, game.ErrInvalidChecksum) {
+		return "Invalid checksum, the code contains a typo"
 	}
-	return ctx.JSON(http.StatusBadRequest, &content)
+	return "Invalid code value"
 }
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/joostvdg/cmg/cmd/context"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/joostvdg/cmg/pkg/game"
@@ -31,8 +33,7 @@ func TestCodeIsUnrecognizable(t *testing.T) {
 	c.SetParamNames("code")
 	c.SetParamValues(unrecognizableCode)
 	cmgContext := &context.CMGContext{
-		c,
-		nil,
+		Context: c,
 	}
 	if assert.NoError(t, GetMapByCode(cmgContext)) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
@@ -58,8 +59,7 @@ func TestCodeIsInvalid(t *testing.T) {
 	c.SetParamNames("code")
 	c.SetParamValues(invalidCode)
 	cmgContext := &context.CMGContext{
-		c,
-		nil,
+		Context: c,
 	}
 	if assert.NoError(t, GetMapByCode(cmgContext)) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
@@ -85,8 +85,7 @@ func TestCodeValidNormalGame(t *testing.T) {
 	c.SetParamNames("code")
 	c.SetParamValues(gameCode)
 	cmgContext := &context.CMGContext{
-		c,
-		nil,
+		Context: c,
 	}
 	if assert.NoError(t, GetMapByCode(cmgContext)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
@@ -99,6 +98,8 @@ func TestCodeValidNormalGame(t *testing.T) {
 	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
 		assert.Equal(t, gameMap.GameCode, gameCode)
 		assert.Equal(t, gameMap.GameType, expectedGameType)
+		assert.Equal(t, 54, len(gameMap.Intersections))
+		assert.Equal(t, 72, len(gameMap.Roads))
 	}
 }
 
@@ -113,8 +114,7 @@ func TestCodeValidNormalGameAlt(t *testing.T) {
 	c.SetParamNames("code")
 	c.SetParamValues(gameCode)
 	cmgContext := &context.CMGContext{
-		c,
-		nil,
+		Context: c,
 	}
 	if assert.NoError(t, GetMapByCode(cmgContext)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
@@ -129,3 +129,116 @@ func TestCodeValidNormalGameAlt(t *testing.T) {
 		assert.Equal(t, gameMap.GameType, expectedGameType)
 	}
 }
+
+func TestCodeValidSeafarersGame(t *testing.T) {
+	gameCode := "2h03g63d47z68j66z67z67z61i17z67z67z64f25e67z67z62e61h67z67z67z67z65f54b67z67z68d61a04c65g37z67z62b67z67z67z63c0"
+	targetPath := fmt.Sprintf("%v/%v/%v", baseApiPath, mapByCodeApiPath, gameCode)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues(gameCode)
+	cmgContext := &context.CMGContext{
+		Context: c,
+	}
+	if assert.NoError(t, GetMapByCode(cmgContext)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	expectedGameType := game.SeafarersGames["seafarers-four-islands"].Name
+
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Equal(t, gameCode, gameMap.GameCode)
+		assert.Equal(t, expectedGameType, gameMap.GameType)
+	}
+}
+
+func TestGetMapSVGByCode(t *testing.T) {
+	code := testGameCode
+	targetPath := fmt.Sprintf("%v/%v/%v.svg", baseApiPath, mapByCodeApiPath, code)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues(code + ".svg")
+	if assert.NoError(t, GetMapByCode(&context.CMGContext{Context: c})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/svg+xml", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "<svg ")
+		assert.Contains(t, rec.Body.String(), code)
+	}
+}
+
+func TestGetMapSVGByCodeIsUnrecognizable(t *testing.T) {
+	targetPath := fmt.Sprintf("%v/%v/abc.svg", baseApiPath, mapByCodeApiPath)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues("abc.svg")
+	if assert.NoError(t, GetMapByCode(&context.CMGContext{Context: c})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var gameMap model.Map
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &gameMap))
+		assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", gameMap.Error)
+	}
+}
+
+func TestGetMapPNGByCode(t *testing.T) {
+	code := testGameCode
+	targetPath := fmt.Sprintf("%v/%v/%v.png", baseApiPath, mapByCodeApiPath, code)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues(code + ".png")
+	if assert.NoError(t, GetMapByCode(&context.CMGContext{Context: c})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+		_, err := png.Decode(rec.Body)
+		assert.NoError(t, err)
+	}
+}
+
+func TestGetMapPDFByCode(t *testing.T) {
+	code := testGameCode
+	targetPath := fmt.Sprintf("%v/%v/%v.pdf", baseApiPath, mapByCodeApiPath, code)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues(code + ".pdf")
+	if assert.NoError(t, GetMapByCode(&context.CMGContext{Context: c})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-"))
+		assert.Contains(t, rec.Body.String(), "/Count 2")
+	}
+}
+
+func TestGetMapPDFByCodeIsInvalid(t *testing.T) {
+	targetPath := fmt.Sprintf("%v/%v/abc.pdf", baseApiPath, mapByCodeApiPath)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("code")
+	c.SetParamValues("abc.pdf")
+	if assert.NoError(t, GetMapByCode(&context.CMGContext{Context: c})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var gameMap model.Map
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &gameMap))
+		assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", gameMap.Error)
+	}
+}
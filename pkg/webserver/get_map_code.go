@@ -19,13 +19,22 @@ func GetMapCode(c echo.Context) error {
 	cmgContext := c.(*context.CMGContext)
 	requestInfo := GetRequestInfoFromRequest(c)
 	rules := GetGameRulesFromRequest(c)
+	options, err := GetGenerationOptionsFromRequest(c)
+	if err != nil {
+		return InvalidParameters(c, err, rules, requestInfo)
+	}
+	locks, err := GetLocksFromRequest(c, rules, options)
+	if err != nil {
+		return InvalidLocks(c, err, rules, requestInfo)
+	}
+	options.Locks = locks
 
-	wholeMap, err := mapgen.ProcessMapGenerationRequest(rules, requestInfo)
+	wholeMap, err := mapgen.ProcessMapGenerationRequest(c.Request().Context(), rules, options, requestInfo)
 	if err != nil {
-		return AbortingMapGeneration(c, rules, requestInfo)
+		return FailedMapGeneration(c, err, rules, options, requestInfo)
 	}
 
-	gameCode := model.GameCode{GameCode: wholeMap.GameCode}
+	gameCode := model.GameCode{GameCode: wholeMap.GameCode, Seed: wholeMap.Seed}
 
 	// TODO what is a userId?
 	if cmgContext.SegmentClient != nil {
@@ -46,19 +55,36 @@ func GetMapCode(c echo.Context) error {
 // GetMapViaCodeGeneration Alternative approach to generating the map
 // instead of generating the map via the structs, we generate a game code
 // and then inflate the game code before validating the board
-// WARNING: currently only supports default normal map
+// Supports every game type of the type parameter, such as type=large
 func GetMapViaCodeGeneration(c echo.Context) error {
 	start := time.Now()
 	cmgContext := c.(*context.CMGContext)
 	log.Info(" > Generate Game by Game Code start")
 	requestInfo := GetRequestInfoFromRequest(c)
 	rules := GetGameRulesFromRequest(c)
+	options, err := GetGenerationOptionsFromRequest(c)
+	if err != nil {
+		return InvalidParameters(c, err, rules, requestInfo)
+	}
+	locks, err := GetLocksFromRequest(c, rules, options)
+	if err != nil {
+		return InvalidLocks(c, err, rules, requestInfo)
+	}
+	options.Locks = locks
 
-	board := mapgen.GenerateBoardByGameCode(rules)
+	board, err := mapgen.GenerateBoardByGameCode(c.Request().Context(), rules, options)
+	if err != nil {
+		return FailedMapGeneration(c, err, rules, options, requestInfo)
+	}
+	intersections, roads := board.Graph()
 	var content = model.Map{
-		GameType: board.GameType.Name,
-		Board:    board.Board,
-		GameCode: board.GameCode,
+		GameType:         board.GameType.Name,
+		Board:            board.Board,
+		HarborDirections: board.HarborDirections,
+		GameCode:         board.GameCode,
+		Seed:             board.Seed,
+		Intersections:    intersections,
+		Roads:            roads,
 	}
 
 	t := time.Now()
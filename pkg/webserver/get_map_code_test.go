@@ -0,0 +1,75 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joostvdg/cmg/cmd/context"
+	"github.com/joostvdg/cmg/pkg/game"
+	boardModel "github.com/joostvdg/cmg/pkg/model"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateMapViaCode(t *testing.T, targetPath string) model.Map {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	cmgContext := &context.CMGContext{
+		Context: c,
+	}
+
+	var gameMap model.Map
+	if assert.NoError(t, GetMapViaCodeGeneration(cmgContext)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap))
+	}
+	return gameMap
+}
+
+func TestGetMapViaCodeGeneration(t *testing.T) {
+	gameMap := generateMapViaCode(t, "/api/v1/map?seed=3")
+	assert.Equal(t, game.NormalGame.Name, gameMap.GameType)
+	assert.Equal(t, int64(3), gameMap.Seed)
+	assert.Equal(t, 57, len(gameMap.GameCode))
+}
+
+func TestGetLargeMapViaCodeGeneration(t *testing.T) {
+	gameMap := generateMapViaCode(t, "/api/v1/map?type=large&seed=3")
+	assert.Equal(t, game.LargeGame.Name, gameMap.GameType)
+	assert.Equal(t, 90, len(gameMap.GameCode))
+	assert.Equal(t, 7, len(gameMap.Board))
+
+	board, err := game.InflateGameFromCode(gameMap.GameCode)
+	if assert.NoError(t, err) {
+		assert.Equal(t, game.LargeGame.Name, board.GameType.Name)
+		harbors := 0
+		for _, tiles := range board.Board {
+			for _, tile := range tiles {
+				if tile.Harbor.Code != boardModel.HarborNone.Code {
+					harbors++
+				}
+			}
+		}
+		assert.Equal(t, 11, harbors)
+		assert.True(t, board.Validate(game.DefaultGameRulesLarge).Valid)
+	}
+}
+
+func TestGetMapViaCodeGenerationFailsAtGenerationLimit(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/map?seed=3&min=999", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	cmgContext := &context.CMGContext{
+		Context: c,
+	}
+
+	if assert.NoError(t, GetMapViaCodeGeneration(cmgContext)) {
+		assert.Equal(t, http.StatusLoopDetected, rec.Code)
+	}
+}
@@ -0,0 +1,67 @@
+package webserver
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/render"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// svgSuffix the suffix of a game code for which the map is returned as an SVG image
+	svgSuffix = ".svg"
+	// pngSuffix the suffix of a game code for which the map is returned as a PNG image
+	pngSuffix = ".png"
+	// pdfSuffix the suffix of a game code for which the map is returned as a printable PDF table sheet
+	pdfSuffix = ".pdf"
+)
+
+// GetMapSVGByCode draws the board of a game code, such as /api/map/code/<code>.svg, as an SVG image
+// A code that can not be inflated results in the same error as for the map itself
+func GetMapSVGByCode(ctx echo.Context) error {
+	return drawMapByCode(ctx, svgSuffix, render.ContentTypeSVG, render.WriteSVG)
+}
+
+// GetMapPNGByCode draws the board of a game code, such as /api/map/code/<code>.png, as a PNG image
+func GetMapPNGByCode(ctx echo.Context) error {
+	return drawMapByCode(ctx, pngSuffix, render.ContentTypePNG, render.WritePNG)
+}
+
+// GetMapPDFByCode writes the table sheet of a game code, such as /api/map/code/<code>.pdf, as a PDF document
+// The QR code on the sheet links to the map of the game code on this server, the request URL without the suffix
+func GetMapPDFByCode(ctx echo.Context) error {
+	request := ctx.Request()
+	url := ctx.Scheme() + "://" + request.Host + strings.TrimSuffix(request.URL.Path, pdfSuffix)
+	return drawMapByCode(ctx, pdfSuffix, render.ContentTypePDF, func(w io.Writer, board *game.Board) error {
+		return render.WritePDF(w, board, url)
+	})
+}
+
+// drawMapByCode inflates the game code without the suffix, and responds with what draw writes for its board
+func drawMapByCode(ctx echo.Context, suffix string, contentType string, draw func(w io.Writer, board *game.Board) error) error {
+	code := strings.TrimSuffix(ctx.Param("code"), suffix)
+
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(ctx, code, err, false, "", func(message string) interface{} {
+			return &model.Map{GameCode: sanitize.Name(code), Error: message}
+		})
+	}
+
+	var image bytes.Buffer
+	if err := draw(&image, &board); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"Code":        sanitize.Name(code),
+		"ContentType": contentType,
+	}).Info("Drew a map")
+	return ctx.Blob(http.StatusOK, contentType, image.Bytes())
+}
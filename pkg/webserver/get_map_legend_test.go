@@ -28,7 +28,7 @@ func TestGetMapLegend(t *testing.T) {
 	var mapLegend model.MapLegend
 
 	expectedNumberOfHarbors := 7
-	expectedNumberOfResources := 6
+	expectedNumberOfResources := 8
 
 	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &mapLegend)) {
 		assert.Equal(t, len(mapLegend.Harbors), expectedNumberOfHarbors)
@@ -0,0 +1,62 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetMapPlacements recommends the opening settlements for the board of a game code, and drafts them for every seat
+// The players parameter is the number of seats, from 3 to 6, and the strategy parameter how the seats pick,
+// greedy or lookahead, other values fall back to the players of the game type and the greedy strategy
+func GetMapPlacements(c echo.Context) error {
+	code := c.Param("code")
+	requestInfo := GetRequestInfoFromRequest(c)
+
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(c, code, err, requestInfo.JSONP, requestInfo.Callback, func(message string) interface{} {
+			return &model.Placements{GameCode: sanitize.Name(code), Error: message}
+		})
+	}
+
+	defaultPlayers := game.StandardPlayers
+	if key, ok := game.DefinedGames.KeyOf(board.GameType); ok {
+		defaultPlayers = game.DraftPlayers(game.DefinedGames[key].Rules)
+	}
+	players := extractIntParamOrDefault(c, "players", defaultPlayers)
+	if players < game.MinPlayers || players > game.MaxPlayers {
+		players = defaultPlayers
+	}
+	strategy, ok := game.ParsePlacementStrategy(c.QueryParam("strategy"))
+	if !ok {
+		strategy = game.PlacementGreedy
+	}
+	draft, err := game.DraftPlacements(&board, players, strategy)
+	if err != nil {
+		log.Warnf("Could not draft the placements for game code %s: %v", sanitize.Name(code), err)
+		return c.JSON(http.StatusInternalServerError, &model.Placements{GameCode: code, Error: err.Error()})
+	}
+
+	log.WithFields(log.Fields{
+		"RequestId":          requestInfo.RequestId,
+		"Code":               sanitize.Name(code),
+		"Players":            players,
+		"Strategy":           strategy,
+		"FirstSeatAdvantage": draft.FirstSeatAdvantage,
+	}).Info("Drafted the placements of a map")
+
+	content := model.Placements{
+		PlacementDraft: draft,
+		GameType:       board.GameType.Name,
+		GameCode:       code,
+	}
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &content)
+	}
+	return c.JSON(http.StatusOK, &content)
+}
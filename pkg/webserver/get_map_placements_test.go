@@ -0,0 +1,52 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func placementsForCode(t *testing.T, code string, query string) (int, model.Placements) {
+	targetPath := fmt.Sprintf("%v/map/code/%v/placements%v", baseApiPath, code, query)
+
+	var placements model.Placements
+	status := callByCode(t, GetMapPlacements, targetPath, code, &placements)
+	return status, placements
+}
+
+func TestGetMapPlacements(t *testing.T) {
+	code := testGameCode
+	for players := game.MinPlayers; players <= game.MaxPlayers; players++ {
+		status, placements := placementsForCode(t, code, fmt.Sprintf("?players=%d&strategy=lookahead", players))
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.Empty(t, placements.Error)
+		assert.Equal(t, code, placements.GameCode)
+		assert.Equal(t, players, placements.Players)
+		assert.Equal(t, game.PlacementLookahead, placements.Strategy)
+		assert.Len(t, placements.Seats, players)
+		assert.Len(t, placements.Picks, players*2)
+		assert.NotEmpty(t, placements.Recommendations)
+	}
+}
+
+func TestGetMapPlacementsFallsBackToDefaults(t *testing.T) {
+	code := testGameCode
+	status, placements := placementsForCode(t, code, "?players=9&strategy=random")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, game.StandardPlayers, placements.Players)
+	assert.Equal(t, game.PlacementGreedy, placements.Strategy)
+}
+
+func TestGetMapPlacementsIsUnrecognizable(t *testing.T) {
+	status, placements := placementsForCode(t, "abc", "")
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", placements.Error)
+	assert.Empty(t, placements.Seats)
+}
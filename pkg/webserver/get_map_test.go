@@ -1,12 +1,14 @@
 package webserver
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/joostvdg/cmg/pkg/game"
 	"github.com/joostvdg/cmg/pkg/webserver/model"
@@ -46,7 +48,7 @@ func TestGetNormalMap(t *testing.T) {
 }
 
 func TestGetLargeMap(t *testing.T) {
-	targetPath := "/api/map?type=large"
+	targetPath := "/api/map?type=large&seed=3"
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
@@ -145,3 +147,356 @@ func TestGetNormalMapJsonPAndCallback(t *testing.T) {
 		assert.Equal(t, gameMap.GameType, expectedGameType)
 	}
 }
+
+func TestGetNormalMapWithSeed(t *testing.T) {
+	targetPath := "/api/map?seed=42"
+
+	gameCodes := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, GetMap(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+		var gameMap model.Map
+		if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+			assert.Equal(t, int64(42), gameMap.Seed)
+			gameCodes = append(gameCodes, gameMap.GameCode)
+		}
+	}
+	assert.Equal(t, gameCodes[0], gameCodes[1])
+}
+
+func TestGetNormalMapWithNoAdjacentRed(t *testing.T) {
+	targetPath := "/api/map?noAdjacentRed=true&seed=3"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		gameType := game.NormalGame
+		board, err := game.InflateNormalGameFromCode(gameMap.GameCode, &gameType)
+		assert.NoError(t, err)
+		rules := game.DefaultGameRulesNormal
+		rules.NoAdjacentRed = true
+		assert.True(t, game.ValidateRedNumbers(&board, rules).Valid)
+	}
+}
+
+func TestGetNormalMapWithoutSameNumbers(t *testing.T) {
+	targetPath := "/api/map?noAdjacentSameNumber=true&noSameNumberPerResource=true&seed=3"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		gameType := game.NormalGame
+		board, err := game.InflateNormalGameFromCode(gameMap.GameCode, &gameType)
+		assert.NoError(t, err)
+		rules := game.DefaultGameRulesNormal
+		rules.NoAdjacentSameNumber = true
+		rules.NoSameNumberPerResource = true
+		assert.True(t, game.ValidateSameNumbers(&board, rules).Valid)
+		assert.True(t, game.ValidateNumbersPerResource(&board, rules).Valid)
+	}
+}
+
+func TestGetSeafarersMap(t *testing.T) {
+	targetPath := "/api/map?type=seafarers-new-shores"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	expectedGameType := game.SeafarersGames["seafarers-new-shores"].Name
+
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Equal(t, expectedGameType, gameMap.GameType)
+		assert.Equal(t, 111, len(gameMap.GameCode))
+		assert.Empty(t, gameMap.Error)
+		assert.Equal(t, 7, len(gameMap.Board))
+		assert.Equal(t, 4, len(gameMap.Board["a"]))
+		assert.Equal(t, 7, len(gameMap.Board["d"]))
+		assert.Equal(t, "Sea", gameMap.Board["a"][0].Landscape.Name)
+	}
+}
+
+func TestGetLargeMapWithBacktracking(t *testing.T) {
+	targetPath := "/api/map?type=large&strategy=backtracking&noAdjacentRed=true&seed=3"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Equal(t, game.LargeGame.Name, gameMap.GameType)
+		assert.Equal(t, 90, len(gameMap.GameCode))
+		board, err := game.InflateGameFromCode(gameMap.GameCode)
+		assert.NoError(t, err)
+		rules := game.DefaultGameRulesLarge
+		rules.NoAdjacentRed = true
+		assert.True(t, board.Validate(rules).Valid)
+	}
+}
+
+func TestGetMapTimeout(t *testing.T) {
+	timeout := GenerationTimeout
+	GenerationTimeout = time.Nanosecond
+	defer func() { GenerationTimeout = timeout }()
+	targetPath := "/api/map?min=1000"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Contains(t, gameMap.Error, "Can not generate a map within")
+	}
+}
+
+func TestGetMapClientGone(t *testing.T) {
+	targetPath := "/api/map?min=1000"
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, GetMap(c))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestGetMapBestEffort(t *testing.T) {
+	targetPath := "/api/map?min=1000&bestEffort=true&seed=1"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.True(t, gameMap.BestEffort)
+		assert.Equal(t, 57, len(gameMap.GameCode))
+		if assert.NotNil(t, gameMap.Validation) {
+			assert.False(t, gameMap.Validation.Valid)
+			failures := gameMap.Validation.Failures()
+			if assert.NotEmpty(t, failures) {
+				assert.Equal(t, "AdjacentTiles", failures[0].Rule)
+			}
+		}
+	}
+}
+
+func TestGetMapWithLocks(t *testing.T) {
+	targetPath := "/api/map?lock=c2:6z6,a0:harbor=0"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Empty(t, gameMap.Error)
+		board, err := game.InflateGameFromCode(gameMap.GameCode)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Desert", board.Board["c"][2].Landscape.Name)
+			assert.Equal(t, "0", board.Board["a"][0].Harbor.Code)
+			assert.True(t, board.Validate(game.DefaultGameRulesNormal).Valid)
+		}
+	}
+}
+
+func TestGetMapWithInvalidLocks(t *testing.T) {
+	targetPath := "/api/map?lock=z9:6z6"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Contains(t, gameMap.Error, "Can not generate a map around the locked tiles")
+		assert.Empty(t, gameMap.GameCode)
+	}
+}
+
+func TestGetMapWithVariableHarbors(t *testing.T) {
+	targetPath := "/api/map?variableHarbors=true&seed=2"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Empty(t, gameMap.Error)
+		assert.Len(t, gameMap.HarborDirections, game.NormalGame.HarborCount)
+		assert.Contains(t, gameMap.GameCode, "~")
+		board, err := game.InflateGameFromCode(gameMap.GameCode)
+		if assert.NoError(t, err) {
+			assert.Equal(t, gameMap.HarborDirections, board.HarborDirections)
+		}
+	}
+}
+
+func TestGetMapWithSpiralNumbers(t *testing.T) {
+	targetPath := "/api/map?numbers=spiral&seed=2"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Empty(t, gameMap.Error)
+		board, err := game.InflateGameFromCode(gameMap.GameCode)
+		if assert.NoError(t, err) {
+			// the tokens A and B, the 5 and the only 2, are next to each other at the start of the spiral
+			assert.True(t, twoNextToFive(&board))
+		}
+	}
+}
+
+func TestGetLargeMapWithSpiralNumbers(t *testing.T) {
+	targetPath := "/api/map?type=large&numbers=spiral&seed=2"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Empty(t, gameMap.Error)
+		board, err := game.InflateGameFromCode(gameMap.GameCode)
+		if assert.NoError(t, err) {
+			assert.Equal(t, game.LargeGame.Name, board.GameType.Name)
+			// the tokens A and B, a 2 and a 5, are next to each other at the start of the spiral
+			assert.True(t, twoNextToFive(&board))
+		}
+	}
+}
+
+// twoNextToFive returns whether a tile with a 2 is next to a tile with a 5 on the board
+func twoNextToFive(board *game.Board) bool {
+	numbers := make(map[string]int)
+	for column, tiles := range board.Board {
+		for row, tile := range tiles {
+			numbers[fmt.Sprintf("%s%d", column, row)] = tile.Number.Number
+		}
+	}
+	for twoPosition, two := range board.GameType.Hexes {
+		for fivePosition, five := range board.GameType.Hexes {
+			if numbers[twoPosition] == 2 && numbers[fivePosition] == 5 && two.IsNeighbour(five) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestGetMapWithUnknownNumbers(t *testing.T) {
+	targetPath := "/api/map?numbers=diagonal"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Contains(t, gameMap.Error, "must be one of random, spiral")
+	}
+}
+
+func TestGetMapWithUnknownStrategy(t *testing.T) {
+	targetPath := "/api/map?strategy=greedy"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Contains(t, gameMap.Error, "must be one of random, backtracking")
+	}
+}
+
+func TestGetMapWithSpiralNumbersAndLockedNumbers(t *testing.T) {
+	targetPath := "/api/map?numbers=spiral&lock=b1:number=8"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, GetMap(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Contains(t, gameMap.Error, "can not be locked")
+	}
+}
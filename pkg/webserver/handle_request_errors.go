@@ -1,6 +1,8 @@
 package webserver
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -8,11 +10,39 @@ import (
 	"github.com/getsentry/sentry-go"
 	sentryecho "github.com/getsentry/sentry-go/echo"
 	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/mapgen"
 	"github.com/joostvdg/cmg/pkg/webserver/model"
 	"github.com/labstack/echo/v4"
 	log "github.com/sirupsen/logrus"
 )
 
+// FailedMapGeneration handles a map generation that did not result in a map
+// When the client went away there is no one to respond to, when the generation took too long it responds with a timeout,
+// otherwise the Generations limit was reached, which is handled by AbortingMapGeneration
+func FailedMapGeneration(ctx echo.Context, err error, rules game.GameRules, options mapgen.GenerationOptions, requestInfo model.RequestInfo) error {
+	if errors.Is(err, context.Canceled) {
+		log.WithFields(log.Fields{
+			"RequestId": requestInfo.RequestId,
+		}).Info("Client went away before a map was generated")
+		return nil
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return AbortingMapGeneration(ctx, rules, requestInfo)
+	}
+
+	message := fmt.Sprintf("Can not generate a map within %v, perhaps try less strict requirements?", options.Timeout)
+	log.Warn(message)
+	var content = model.Map{
+		GameType: rules.GameTypeString,
+		Board:    nil,
+		Error:    message,
+	}
+	if requestInfo.JSONP {
+		return ctx.JSONP(http.StatusServiceUnavailable, requestInfo.Callback, &content)
+	}
+	return ctx.JSON(http.StatusServiceUnavailable, &content)
+}
+
 // AbortingMapGeneration handles aborting the attempt to generate a map, handle the error and send a response to the affected client
 func AbortingMapGeneration(ctx echo.Context, rules game.GameRules, requestInfo model.RequestInfo) error {
 	if hub := sentryecho.GetHubFromContext(ctx); hub != nil {
@@ -36,3 +66,33 @@ func AbortingMapGeneration(ctx echo.Context, rules game.GameRules, requestInfo m
 	}
 	return ctx.JSON(http.StatusLoopDetected, &content)
 }
+
+// InvalidLocks responds that the locked tiles of the request can not be on the board of the requested game type
+func InvalidLocks(ctx echo.Context, err error, rules game.GameRules, requestInfo model.RequestInfo) error {
+	message := fmt.Sprintf("Can not generate a map around the locked tiles, reason: %v", err)
+	log.Warn(message)
+	var content = model.Map{
+		GameType: rules.GameTypeString,
+		Board:    nil,
+		Error:    message,
+	}
+	if requestInfo.JSONP {
+		return ctx.JSONP(http.StatusBadRequest, requestInfo.Callback, &content)
+	}
+	return ctx.JSON(http.StatusBadRequest, &content)
+}
+
+// InvalidParameters responds that the options of the request, such as the strategy or the objective, do not exist
+func InvalidParameters(ctx echo.Context, err error, rules game.GameRules, requestInfo model.RequestInfo) error {
+	message := fmt.Sprintf("Can not generate a map with the requested options, reason: %v", err)
+	log.Warn(message)
+	var content = model.Map{
+		GameType: rules.GameTypeString,
+		Board:    nil,
+		Error:    message,
+	}
+	if requestInfo.JSONP {
+		return ctx.JSONP(http.StatusBadRequest, requestInfo.Callback, &content)
+	}
+	return ctx.JSON(http.StatusBadRequest, &content)
+}
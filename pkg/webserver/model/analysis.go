@@ -0,0 +1,9 @@
+package model
+
+import "github.com/joostvdg/cmg/pkg/analysis"
+
+// Analysis the fairness metrics of the board of a game code, or the reason the code could not be analyzed
+type Analysis struct {
+	analysis.Analysis
+	Error string
+}
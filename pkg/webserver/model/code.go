@@ -0,0 +1,11 @@
+package model
+
+// ConvertedCode a game code converted into another format, or the reason the code could not be converted
+type ConvertedCode struct {
+	GameType        string
+	Code            string
+	Format          string
+	ConvertedCode   string
+	ConvertedFormat string
+	Error           string
+}
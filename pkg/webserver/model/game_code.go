@@ -2,4 +2,5 @@ package model
 
 type GameCode struct {
 	GameCode string
+	Seed     int64
 }
@@ -0,0 +1,13 @@
+package model
+
+import "github.com/joostvdg/cmg/pkg/game"
+
+// GameTypeListing a game type that maps can be generated for, selected by its Key in the type parameter,
+// with the default rules for generating its maps
+type GameTypeListing struct {
+	Key        string
+	Name       string
+	CodeTag    string
+	TilesCount int
+	Rules      game.GameRules
+}
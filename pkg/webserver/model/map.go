@@ -1,11 +1,27 @@
 package model
 
-import "github.com/joostvdg/cmg/pkg/model"
+import (
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/model"
+)
 
 // Map the Catan Map, a wrapper around the Game Board
+// Intersections and Roads are the places on the board where players build
+// Attempts is the number of boards that were generated to find the map
+// BestEffort is set when no map satisfied the rules, the Map is then the closest one, and Validation shows why it is not valid
+// Optimization is set when the map was optimized for a more balanced board
+// HarborDirections is only set for maps with variable harbors, as the index of the direction of the coast each harbor faces
 type Map struct {
-	GameType string
-	Board    map[string][]*model.Tile
-	GameCode string
-	Error    string
+	GameType         string
+	Board            map[string][]*model.Tile
+	HarborDirections game.HarborDirections
+	GameCode         string
+	Seed             int64
+	Attempts         int
+	Intersections    []model.Intersection
+	Roads            []model.Road
+	BestEffort       bool
+	Validation       *game.ValidationReport
+	Optimization     *Optimization
+	Error            string
 }
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/joostvdg/cmg/pkg/game"
+)
+
+// MapRequest the JSON body of a request for maps on /api/v2/map
+// The Rules have the fields of the game.GameRules, a rule that is left out is the default of the game type of the rules
+// An empty Strategy, Numbers or Output is the default, a Seed of 0 a random seed, and a Count that is left out a single map
+// A Seed can not be negative, as the consecutive seeds of a Count would pass 0
+type MapRequest struct {
+	Rules      map[string]json.RawMessage `json:"rules"`
+	Seed       int64                      `json:"seed"`
+	Strategy   string                     `json:"strategy"`
+	Numbers    string                     `json:"numbers"`
+	BestEffort bool                       `json:"bestEffort"`
+	Count      int                        `json:"count"`
+	Output     string                     `json:"output"`
+}
+
+// FieldError why a field of a request is not valid, the Field is its path in the JSON body, such as rules.minimumScore
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// GeneratedMaps the maps generated for a MapRequest, with the rules that were applied to them
+// When the request is not valid, the Errors explain which of its fields are not valid, and there are no maps
+type GeneratedMaps struct {
+	GameType string
+	Rules    game.GameRules
+	Maps     []GeneratedMap
+	Errors   []FieldError
+	Error    string
+}
+
+// GeneratedMap a map generated for a MapRequest, with the seed to generate it again, the number of boards that were
+// generated to find it, and how long that took in milliseconds
+// With an image as the output, the Image holds the map in the ContentType, base64 encoded in JSON, instead of the Map
+type GeneratedMap struct {
+	GameCode    string
+	Seed        int64
+	Attempts    int
+	DurationMs  int64
+	Map         *Map
+	ContentType string
+	Image       []byte
+}
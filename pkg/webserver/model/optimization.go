@@ -0,0 +1,12 @@
+package model
+
+// Optimization the result of optimizing a map for an objective, lower scores are better
+// The Trajectory holds the score of the best map found so far, sampled during the optimization
+type Optimization struct {
+	Objective    string
+	Iterations   int
+	Accepted     int
+	InitialScore float64
+	FinalScore   float64
+	Trajectory   []float64
+}
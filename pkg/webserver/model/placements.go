@@ -0,0 +1,12 @@
+package model
+
+import "github.com/joostvdg/cmg/pkg/game"
+
+// Placements the recommended opening settlements for the board of a game code, and the draft of them by every seat,
+// or the reason the code could not be drafted
+type Placements struct {
+	game.PlacementDraft
+	GameType string
+	GameCode string
+	Error    string
+}
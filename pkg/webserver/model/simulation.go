@@ -0,0 +1,11 @@
+package model
+
+import "github.com/joostvdg/cmg/pkg/analysis"
+
+// Simulation the simulated yields of the board of a game code, with the seed to reproduce them,
+// or the reason the code could not be simulated
+type Simulation struct {
+	analysis.Simulation
+	Seed  int64
+	Error string
+}
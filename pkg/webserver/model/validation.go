@@ -0,0 +1,12 @@
+package model
+
+import "github.com/joostvdg/cmg/pkg/game"
+
+// Validation the validation report of the board of a game code, explains which rules the board does not satisfy
+type Validation struct {
+	GameType string
+	GameCode string
+	Valid    bool
+	Results  []game.ValidationResult
+	Error    string
+}
@@ -0,0 +1,38 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/mapgen"
+	"github.com/labstack/echo/v4"
+)
+
+// OptimizeMap generates a map like GetMap, and then optimizes it for a more balanced board
+// The objective parameter is what to minimize, and the iterations parameter how many swaps to try
+func OptimizeMap(c echo.Context) error {
+	requestInfo := GetRequestInfoFromRequest(c)
+	rules := GetGameRulesFromRequest(c)
+	options, err := GetGenerationOptionsFromRequest(c)
+	if err != nil {
+		return InvalidParameters(c, err, rules, requestInfo)
+	}
+	locks, err := GetLocksFromRequest(c, rules, options)
+	if err != nil {
+		return InvalidLocks(c, err, rules, requestInfo)
+	}
+	options.Locks = locks
+	options.Optimization, err = GetOptimizationOptionsFromRequest(c)
+	if err != nil {
+		return InvalidParameters(c, err, rules, requestInfo)
+	}
+
+	gameMap, err := mapgen.ProcessMapGenerationRequest(c.Request().Context(), rules, options, requestInfo)
+	if err != nil {
+		return FailedMapGeneration(c, err, rules, options, requestInfo)
+	}
+
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &gameMap)
+	}
+	return c.JSON(http.StatusOK, &gameMap)
+}
@@ -0,0 +1,57 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimizeMap(t *testing.T) {
+	targetPath := "/api/map/optimize?objective=maxTileGroup&iterations=200&seed=8"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, OptimizeMap(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Equal(t, game.NormalGame.Name, gameMap.GameType)
+		assert.Equal(t, 57, len(gameMap.GameCode))
+		if assert.NotNil(t, gameMap.Optimization) {
+			assert.Equal(t, "maxTileGroup", gameMap.Optimization.Objective)
+			assert.Equal(t, 200, gameMap.Optimization.Iterations)
+			assert.LessOrEqual(t, gameMap.Optimization.FinalScore, gameMap.Optimization.InitialScore)
+			assert.NotEmpty(t, gameMap.Optimization.Trajectory)
+		}
+		board, err := game.InflateGameFromCode(gameMap.GameCode)
+		assert.NoError(t, err)
+		assert.True(t, board.Validate(game.DefaultGameRulesNormal).Valid)
+	}
+}
+
+func TestOptimizeMapWithUnknownObjective(t *testing.T) {
+	targetPath := "/api/map/optimize?objective=fairest&seed=8"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, targetPath, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, OptimizeMap(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	var gameMap model.Map
+	if assert.NoError(t, json.Unmarshal([]byte(rec.Body.String()), &gameMap)) {
+		assert.Contains(t, gameMap.Error, "must be one of intersectionVariance, resourceSpread, maxTileGroup")
+	}
+}
@@ -0,0 +1,301 @@
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/mapgen"
+	"github.com/joostvdg/cmg/pkg/render"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// outputJSON the output of the maps themselves, the default
+const outputJSON = "json"
+
+// mapOutput an image a map can be returned as, instead of the map itself
+type mapOutput struct {
+	contentType string
+	draw        func(w io.Writer, board *game.Board) error
+}
+
+// mapOutputs the images the maps of a request can be returned as, by the name of the output
+var mapOutputs = map[string]mapOutput{
+	"svg": {contentType: render.ContentTypeSVG, draw: render.WriteSVG},
+	"png": {contentType: render.ContentTypePNG, draw: render.WritePNG},
+}
+
+// PostMapV2 generates maps for the rules and options in the JSON body of the request, a model.MapRequest
+// Unlike the query parameters of the other endpoints, a field that is not valid does not fall back to its default,
+// the request is rejected with an error for every field that is not valid
+// With a Count, the maps are generated from consecutive seeds, starting at the Seed of the request when it has one,
+// and all of them have to be generated within the GenerationTimeout
+func PostMapV2(c echo.Context) error {
+	requestInfo := GetRequestInfoFromRequest(c)
+	request, rules, fieldErrors := parseMapRequest(c.Request().Body)
+	content := model.GeneratedMaps{
+		GameType: rules.GameTypeString,
+		Rules:    rules,
+	}
+	if len(fieldErrors) > 0 {
+		content.Errors = fieldErrors
+		content.Error = fmt.Sprintf("Invalid map request, %d fields are not valid", len(fieldErrors))
+		log.Warn(content.Error)
+		return c.JSON(http.StatusBadRequest, &content)
+	}
+
+	strategy, _ := mapgen.ParseStrategy(request.Strategy)
+	numbers, _ := mapgen.ParseNumberPlacement(request.Numbers)
+	ctx, cancel := context.WithTimeout(c.Request().Context(), GenerationTimeout)
+	defer cancel()
+
+	for i := 0; i < request.Count; i++ {
+		options := mapgen.GenerationOptions{
+			Seed:       request.Seed,
+			Strategy:   strategy,
+			Timeout:    GenerationTimeout,
+			BestEffort: request.BestEffort,
+			Numbers:    numbers,
+		}
+		if request.Seed != 0 {
+			options.Seed = request.Seed + int64(i)
+		}
+		start := time.Now()
+		gameMap, err := mapgen.ProcessMapGenerationRequest(ctx, rules, options, requestInfo)
+		if err != nil {
+			return FailedMapGeneration(c, err, rules, options, requestInfo)
+		}
+
+		generated := model.GeneratedMap{
+			GameCode:   gameMap.GameCode,
+			Seed:       gameMap.Seed,
+			Attempts:   gameMap.Attempts,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if output, ok := mapOutputs[request.Output]; ok {
+			board, err := game.InflateGameFromCode(gameMap.GameCode)
+			if err != nil {
+				return err
+			}
+			var image bytes.Buffer
+			if err := output.draw(&image, &board); err != nil {
+				return err
+			}
+			generated.ContentType = output.contentType
+			generated.Image = image.Bytes()
+		} else {
+			generated.Map = &gameMap
+		}
+		content.Maps = append(content.Maps, generated)
+	}
+
+	log.WithFields(log.Fields{
+		"RequestId": requestInfo.RequestId,
+		"GameType":  rules.GameTypeString,
+		"Maps":      len(content.Maps),
+	}).Info("Generated the maps of a map request")
+	return c.JSON(http.StatusOK, &content)
+}
+
+// parseMapRequest decodes the JSON body of a request for maps, with the rules it asks for
+// Returns an error for every field of the body that is not valid, after which the request and rules are incomplete
+func parseMapRequest(body io.Reader) (model.MapRequest, game.GameRules, []model.FieldError) {
+	var request model.MapRequest
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&fields); err != nil || fields == nil {
+		_, definedGame := game.DefinedGames.ForRules(game.GameRules{})
+		return request, definedGame.Rules, []model.FieldError{{Field: "", Message: "the body must be a JSON object"}}
+	}
+
+	fieldErrors := decodeFields(fields, "", &request, "is not an option of a map request")
+	if !hasField(fields, "count") {
+		request.Count = 1
+	}
+	rules, ruleErrors := parseRules(request.Rules)
+	fieldErrors = append(fieldErrors, ruleErrors...)
+	fieldErrors = append(fieldErrors, verifyMapRequest(request)...)
+	return request, rules, fieldErrors
+}
+
+// parseRules decodes the rules of a map request over the default rules of the game type they are for
+// The game type is the key of a game type in the gameTypeString, or else its number in the gameType, and the Normal game
+// when the rules have neither, with both the gameType has to be the number of the game type of the gameTypeString
+func parseRules(fields map[string]json.RawMessage) (game.GameRules, []model.FieldError) {
+	gameTypeFields := make(map[string]json.RawMessage)
+	otherFields := make(map[string]json.RawMessage)
+	for field, value := range fields {
+		// like the other fields, the fields of the game type are matched regardless of case
+		if strings.EqualFold(field, "gameType") || strings.EqualFold(field, "gameTypeString") {
+			gameTypeFields[field] = value
+		} else {
+			otherFields[field] = value
+		}
+	}
+	var requested game.GameRules
+	fieldErrors := decodeFields(gameTypeFields, "rules.", &requested, "is not a rule")
+
+	gameTypeKey := ""
+	var definedGame game.DefinedGame
+	if requested.GameTypeString != "" {
+		key, found, ok := game.DefinedGames.Lookup(requested.GameTypeString)
+		if ok {
+			gameTypeKey, definedGame = key, found
+		} else {
+			fieldErrors = append(fieldErrors, model.FieldError{Field: "rules.gameTypeString",
+				Message: fmt.Sprintf("%s is not a game type, see /api/gametypes", sanitize.Name(requested.GameTypeString))})
+		}
+	}
+	if hasField(gameTypeFields, "gameType") {
+		if requested.GameTypeString != "" {
+			// the number of the game type in its default rules, which the game types of Seafarers share
+			if gameTypeKey != "" && requested.GameType != definedGame.Rules.GameType {
+				fieldErrors = append(fieldErrors, model.FieldError{Field: "rules.gameType",
+					Message: fmt.Sprintf("must be %d, the number of game type %s", definedGame.Rules.GameType, gameTypeKey)})
+			}
+		} else if key, found, ok := game.DefinedGames.Lookup(strconv.Itoa(requested.GameType)); ok {
+			gameTypeKey, definedGame = key, found
+		} else {
+			fieldErrors = append(fieldErrors, model.FieldError{Field: "rules.gameType",
+				Message: fmt.Sprintf("%d is not the number of a game type, use the gameTypeString", requested.GameType)})
+		}
+	}
+	if gameTypeKey == "" {
+		gameTypeKey, definedGame = game.DefinedGames.ForRules(game.GameRules{})
+	}
+
+	rules := definedGame.Rules
+	fieldErrors = append(fieldErrors, decodeFields(otherFields, "rules.", &rules, "is not a rule")...)
+	rules.GameType = definedGame.Rules.GameType
+	rules.GameTypeString = gameTypeKey
+	fieldErrors = append(fieldErrors, verifyRules(rules, definedGame.Rules)...)
+	return rules, fieldErrors
+}
+
+// verifyRules returns an error for every rule that can not be satisfied, or that a request may not change
+// The Generations can be lowered, but not raised above the default of the game type
+func verifyRules(rules game.GameRules, defaults game.GameRules) []model.FieldError {
+	fieldErrors := make([]model.FieldError, 0)
+	check := func(valid bool, field string, format string, values ...interface{}) {
+		if !valid {
+			fieldErrors = append(fieldErrors, model.FieldError{Field: "rules." + field, Message: fmt.Sprintf(format, values...)})
+		}
+	}
+	check(rules.MinimumScore >= 0, "minimumScore", "must not be negative")
+	check(rules.MaximumScore >= rules.MinimumScore, "maximumScore", "must be at least the minimumScore of %d", rules.MinimumScore)
+	check(rules.MinimumResourceScore >= 0, "minimumResourceScore", "must not be negative")
+	check(rules.MaximumResourceScore >= rules.MinimumResourceScore, "maximumResourceScore",
+		"must be at least the minimumResourceScore of %d", rules.MinimumResourceScore)
+	check(rules.MaxOver300 >= 0, "maxOver300", "must not be negative")
+	check(rules.MaxSameLandscapePerRow >= 1, "maxSameLandscapePerRow", "must be at least 1")
+	check(rules.MaxSameLandscapePerColumn >= 1, "maxSameLandscapePerColumn", "must be at least 1")
+	check(rules.AdjacentSame >= 0, "adjacentSame", "must not be negative")
+	check(rules.MaxFirstSeatAdvantage >= 0, "maxFirstSeatAdvantage", "must not be negative, 0 is not checked")
+	check(rules.MaxSeatGap >= 0, "maxSeatGap", "must not be negative, 0 is not checked")
+	check(rules.Players >= game.MinPlayers && rules.Players <= game.MaxPlayers, "players",
+		"must be between %d and %d", game.MinPlayers, game.MaxPlayers)
+	check(rules.Generations >= 1 && rules.Generations <= defaults.Generations, "generations",
+		"must be between 1 and %d", defaults.Generations)
+	check(rules.Delimiter == defaults.Delimiter, "delimiter", "can only be %q", defaults.Delimiter)
+	return fieldErrors
+}
+
+// verifyMapRequest returns an error for every option of the map request that does not exist
+func verifyMapRequest(request model.MapRequest) []model.FieldError {
+	fieldErrors := make([]model.FieldError, 0)
+	if _, ok := mapgen.ParseStrategy(request.Strategy); !ok {
+		names := make([]string, 0, len(mapgen.Strategies))
+		for _, strategy := range mapgen.Strategies {
+			names = append(names, string(strategy))
+		}
+		fieldErrors = append(fieldErrors, model.FieldError{Field: "strategy", Message: "must be one of " + strings.Join(names, ", ")})
+	}
+	if _, ok := mapgen.ParseNumberPlacement(request.Numbers); !ok {
+		fieldErrors = append(fieldErrors, model.FieldError{Field: "numbers", Message: "must be one of " + strings.Join(sortedKeys(mapgen.NumberPlacements), ", ")})
+	}
+	if request.Seed < 0 {
+		// the maps of a Count are generated from consecutive seeds, which from a negative seed could reach 0, a random seed
+		fieldErrors = append(fieldErrors, model.FieldError{Field: "seed", Message: "must not be negative, 0 is a random seed"})
+	}
+	if request.Count < 1 || request.Count > maxMapsPerRequest {
+		fieldErrors = append(fieldErrors, model.FieldError{Field: "count", Message: fmt.Sprintf("must be between 1 and %d", maxMapsPerRequest)})
+	}
+	if _, ok := mapOutputs[request.Output]; !ok && request.Output != "" && request.Output != outputJSON {
+		outputs := append([]string{outputJSON}, sortedKeys(mapOutputs)...)
+		fieldErrors = append(fieldErrors, model.FieldError{Field: "output", Message: "must be one of " + strings.Join(outputs, ", ")})
+	}
+	return fieldErrors
+}
+
+// decodeFields decodes every field of a JSON object into the target on its own, so every field that the target does not have,
+// or that has a value of the wrong type, gets its own error, with the path of the object in front of the name of the field
+func decodeFields(fields map[string]json.RawMessage, path string, target interface{}, unknown string) []model.FieldError {
+	fieldErrors := make([]model.FieldError, 0)
+	for _, field := range sortedKeys(fields) {
+		single, err := json.Marshal(map[string]json.RawMessage{field: fields[field]})
+		if err == nil {
+			decoder := json.NewDecoder(bytes.NewReader(single))
+			decoder.DisallowUnknownFields()
+			err = decoder.Decode(target)
+		}
+		if err == nil {
+			continue
+		}
+		message := "is not valid"
+		var typeError *json.UnmarshalTypeError
+		if errors.As(err, &typeError) {
+			message = fmt.Sprintf("must be %s, not %s", jsonTypeName(typeError.Type), typeError.Value)
+		} else if strings.HasPrefix(err.Error(), "json: unknown field") {
+			message = unknown
+		}
+		fieldErrors = append(fieldErrors, model.FieldError{Field: path + field, Message: message})
+	}
+	return fieldErrors
+}
+
+// jsonTypeName the name of the JSON type that is decoded into a Go type, for the errors of the fields of a request
+func jsonTypeName(goType reflect.Type) string {
+	switch goType.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "a whole number"
+	case reflect.String:
+		return "a string"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return goType.String()
+	}
+}
+
+// hasField returns whether the JSON object has the field, regardless of case
+func hasField(fields map[string]json.RawMessage, name string) bool {
+	for field := range fields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
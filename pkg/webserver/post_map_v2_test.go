@@ -0,0 +1,133 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/render"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func postMapV2(t *testing.T, body string) (int, model.GeneratedMaps) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/map", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var generated model.GeneratedMaps
+	if assert.NoError(t, PostMapV2(c)) {
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &generated))
+	}
+	return rec.Code, generated
+}
+
+func TestPostMapV2(t *testing.T) {
+	code, generated := postMapV2(t, `{"rules": {"gameTypeString": "large", "minimumScore": 150}, "seed": 3, "count": 2}`)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, generated.Error)
+	assert.Equal(t, "large", generated.GameType)
+	assert.Equal(t, 150, generated.Rules.MinimumScore)
+	assert.Equal(t, game.DefaultGameRulesLarge.MaximumScore, generated.Rules.MaximumScore)
+	if assert.Len(t, generated.Maps, 2) {
+		for i, generatedMap := range generated.Maps {
+			assert.Equal(t, int64(3+i), generatedMap.Seed)
+			assert.Positive(t, generatedMap.Attempts)
+			if assert.NotNil(t, generatedMap.Map) {
+				assert.Equal(t, generatedMap.GameCode, generatedMap.Map.GameCode)
+				assert.Equal(t, game.LargeGame.Name, generatedMap.Map.GameType)
+			}
+			board, err := game.InflateGameFromCode(generatedMap.GameCode)
+			if assert.NoError(t, err) {
+				assert.True(t, board.Validate(generated.Rules).Valid)
+			}
+		}
+	}
+}
+
+func TestPostMapV2AsImage(t *testing.T) {
+	code, generated := postMapV2(t, `{"seed": 2, "output": "svg"}`)
+
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, generated.Maps, 1) {
+		assert.Nil(t, generated.Maps[0].Map)
+		assert.Equal(t, render.ContentTypeSVG, generated.Maps[0].ContentType)
+		assert.Contains(t, string(generated.Maps[0].Image), "<svg")
+	}
+}
+
+func TestPostMapV2WithInvalidFields(t *testing.T) {
+	body := `{
+		"rules": {"maximumScore": "abc", "minimumScore": -1, "players": 9, "maxSeatGap": 1.5, "robber": true},
+		"strategy": "sideways", "count": 50, "output": "gif", "jsonp": true
+	}`
+	code, generated := postMapV2(t, body)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Empty(t, generated.Maps)
+	fields := make(map[string]string)
+	for _, fieldError := range generated.Errors {
+		fields[fieldError.Field] = fieldError.Message
+	}
+	assert.Equal(t, map[string]string{
+		"jsonp":              "is not an option of a map request",
+		"rules.maximumScore": "must be a whole number, not string",
+		"rules.maxSeatGap":   "must be a whole number, not number 1.5",
+		"rules.robber":       "is not a rule",
+		"rules.minimumScore": "must not be negative",
+		"rules.players":      "must be between 3 and 6",
+		"strategy":           "must be one of random, backtracking",
+		"count":              "must be between 1 and 10",
+		"output":             "must be one of json, png, svg",
+	}, fields)
+	assert.Contains(t, generated.Error, "9 fields are not valid")
+}
+
+func TestPostMapV2WithZeroCount(t *testing.T) {
+	code, generated := postMapV2(t, `{"count": 0}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []model.FieldError{{Field: "count", Message: "must be between 1 and 10"}}, generated.Errors)
+}
+
+func TestPostMapV2WithNegativeSeed(t *testing.T) {
+	code, generated := postMapV2(t, `{"seed": -2, "count": 3}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []model.FieldError{{Field: "seed", Message: "must not be negative, 0 is a random seed"}}, generated.Errors)
+}
+
+func TestPostMapV2WithInvalidGameType(t *testing.T) {
+	code, generated := postMapV2(t, `{"rules": {"gameTypeString": "chess"}}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []model.FieldError{{Field: "rules.gameTypeString", Message: "chess is not a game type, see /api/gametypes"}}, generated.Errors)
+
+	code, generated = postMapV2(t, `{"rules": {"gameTypeString": "large", "gameType": 0}}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []model.FieldError{{Field: "rules.gameType", Message: "must be 1, the number of game type large"}}, generated.Errors)
+
+	code, generated = postMapV2(t, `not json`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []model.FieldError{{Field: "", Message: "the body must be a JSON object"}}, generated.Errors)
+}
+
+func TestParseRulesOfGameTypes(t *testing.T) {
+	// the rules of the game types as listed by /api/gametypes are valid rules for a map request
+	for _, key := range game.DefinedGames.Keys() {
+		rules := game.DefinedGames[key].Rules
+		rules.GameTypeString = key
+		listed, err := json.Marshal(rules)
+		assert.NoError(t, err)
+		var fields map[string]json.RawMessage
+		assert.NoError(t, json.Unmarshal(listed, &fields))
+
+		parsed, fieldErrors := parseRules(fields)
+		assert.Empty(t, fieldErrors, key)
+		assert.Equal(t, rules, parsed, key)
+	}
+}
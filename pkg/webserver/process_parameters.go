@@ -1,14 +1,34 @@
 package webserver
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/joostvdg/cmg/pkg/analysis"
 	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/mapgen"
 	"github.com/joostvdg/cmg/pkg/webserver/model"
 	"github.com/labstack/echo/v4"
 )
 
+// GenerationTimeout the maximum time to spend on generating a map for a request
+var GenerationTimeout = 30 * time.Second
+
+// The most work a single request can ask for, larger values fall back to the default or are rejected
+const (
+	// maxOptimizationIterations the most swaps the optimizer tries for a map
+	maxOptimizationIterations = 100000
+	// maxSimulationGames the most games a simulation rolls the dice for
+	maxSimulationGames = 10000
+	// maxSimulationTurns the most turns of each simulated game
+	maxSimulationTurns = 500
+	// maxMapsPerRequest the most maps a request on /api/v2/map generates
+	maxMapsPerRequest = 10
+)
+
 func extractIntParamOrDefault(context echo.Context, paramName string, defaultValue int) int {
 	paramValue := context.QueryParam(paramName)
 	if len(paramValue) <= 0 {
@@ -21,35 +41,62 @@ func extractIntParamOrDefault(context echo.Context, paramName string, defaultVal
 	return intValue
 }
 
+func extractInt64ParamOrDefault(context echo.Context, paramName string, defaultValue int64) int64 {
+	paramValue := context.QueryParam(paramName)
+	if len(paramValue) <= 0 {
+		return defaultValue
+	}
+	intValue, err := strconv.ParseInt(paramValue, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return intValue
+}
+
+func extractBoolParamOrDefault(context echo.Context, paramName string, defaultValue bool) bool {
+	paramValue := context.QueryParam(paramName)
+	if len(paramValue) <= 0 {
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(paramValue)
+	if err != nil {
+		return defaultValue
+	}
+	return boolValue
+}
+
+// GetGameRulesFromRequest retrieves the GameRules from the query parameters
+// Parameters that are not supplied fall back to the default rules of the requested game type
 func GetGameRulesFromRequest(c echo.Context) game.GameRules {
-	gameTypeValue := 0
-	gameTypeParam := c.QueryParam("type")
-	if gameTypeParam == "large" {
-		gameTypeValue = 1
-	}
-
-	min := extractIntParamOrDefault(c, "min", game.DefaultGameRulesNormal.MinimumScore)
-	max := extractIntParamOrDefault(c, "max", game.DefaultGameRulesNormal.MaximumScore)
-	max300 := extractIntParamOrDefault(c, "max300", game.DefaultGameRulesNormal.MaxOver300)
-	maxr := extractIntParamOrDefault(c, "maxr", game.DefaultGameRulesNormal.MaximumResourceScore)
-	minr := extractIntParamOrDefault(c, "minr", game.DefaultGameRulesNormal.MinimumResourceScore)
-	maxRow := extractIntParamOrDefault(c, "maxRow", game.DefaultGameRulesNormal.MaxSameLandscapePerRow)
-	maxColumn := extractIntParamOrDefault(c, "maxColumn", game.DefaultGameRulesNormal.MaxSameLandscapePerColumn)
-	adjacentSame := extractIntParamOrDefault(c, "adjacentSame", game.DefaultGameRulesNormal.AdjacentSame)
-
-	if gameTypeParam == "large" {
-		min = extractIntParamOrDefault(c, "min", game.DefaultGameRulesLarge.MinimumScore)
-		max = extractIntParamOrDefault(c, "max", game.DefaultGameRulesLarge.MaximumScore)
-		max300 = extractIntParamOrDefault(c, "max300", game.DefaultGameRulesLarge.MaxOver300)
-		maxr = extractIntParamOrDefault(c, "maxr", game.DefaultGameRulesLarge.MaximumResourceScore)
-		minr = extractIntParamOrDefault(c, "minr", game.DefaultGameRulesLarge.MinimumResourceScore)
-		maxRow = extractIntParamOrDefault(c, "maxRow", game.DefaultGameRulesLarge.MaxSameLandscapePerRow)
-		maxColumn = extractIntParamOrDefault(c, "maxColumn", game.DefaultGameRulesLarge.MaxSameLandscapePerColumn)
-		adjacentSame = extractIntParamOrDefault(c, "adjacentSame", game.DefaultGameRulesLarge.AdjacentSame)
+	return getGameRulesForGameType(c, c.QueryParam("type"))
+}
+
+func getGameRulesForGameType(c echo.Context, gameTypeParam string) game.GameRules {
+	// an unknown game type falls back to the Normal game
+	gameTypeKey, definedGame := game.DefinedGames.ForRules(game.GameRules{GameTypeString: gameTypeParam})
+	defaultRules := definedGame.Rules
+
+	min := extractIntParamOrDefault(c, "min", defaultRules.MinimumScore)
+	max := extractIntParamOrDefault(c, "max", defaultRules.MaximumScore)
+	max300 := extractIntParamOrDefault(c, "max300", defaultRules.MaxOver300)
+	maxr := extractIntParamOrDefault(c, "maxr", defaultRules.MaximumResourceScore)
+	minr := extractIntParamOrDefault(c, "minr", defaultRules.MinimumResourceScore)
+	maxRow := extractIntParamOrDefault(c, "maxRow", defaultRules.MaxSameLandscapePerRow)
+	maxColumn := extractIntParamOrDefault(c, "maxColumn", defaultRules.MaxSameLandscapePerColumn)
+	adjacentSame := extractIntParamOrDefault(c, "adjacentSame", defaultRules.AdjacentSame)
+	noAdjacentRed := extractBoolParamOrDefault(c, "noAdjacentRed", defaultRules.NoAdjacentRed)
+	noAdjacentSameNumber := extractBoolParamOrDefault(c, "noAdjacentSameNumber", defaultRules.NoAdjacentSameNumber)
+	noSameNumberPerResource := extractBoolParamOrDefault(c, "noSameNumberPerResource", defaultRules.NoSameNumberPerResource)
+	maxFirstSeatAdvantage := extractIntParamOrDefault(c, "maxFirstSeatAdvantage", defaultRules.MaxFirstSeatAdvantage)
+	maxSeatGap := extractIntParamOrDefault(c, "maxSeatGap", defaultRules.MaxSeatGap)
+	variableHarbors := extractBoolParamOrDefault(c, "variableHarbors", defaultRules.VariableHarbors)
+	players := extractIntParamOrDefault(c, "players", defaultRules.Players)
+	if players < game.MinPlayers || players > game.MaxPlayers {
+		players = defaultRules.Players
 	}
 
 	rules := game.GameRules{
-		GameType:                  gameTypeValue,
+		GameType:                  defaultRules.GameType,
 		MinimumScore:              min,
 		MaximumScore:              max,
 		MaxOver300:                max300,
@@ -58,13 +105,110 @@ func GetGameRulesFromRequest(c echo.Context) game.GameRules {
 		MaxSameLandscapePerRow:    maxRow,
 		MaxSameLandscapePerColumn: maxColumn,
 		AdjacentSame:              adjacentSame,
-		Generations:               game.DefaultGameRulesNormal.Generations,
-		GameTypeString:            gameTypeParam,
+		NoAdjacentRed:             noAdjacentRed,
+		NoAdjacentSameNumber:      noAdjacentSameNumber,
+		NoSameNumberPerResource:   noSameNumberPerResource,
+		MaxFirstSeatAdvantage:     maxFirstSeatAdvantage,
+		MaxSeatGap:                maxSeatGap,
+		Players:                   players,
+		Generations:               defaultRules.Generations,
+		GameTypeString:            gameTypeKey,
+		VariableHarbors:           variableHarbors,
 	}
 
 	return rules
 }
 
+// GetGenerationOptionsFromRequest retrieves the options for how to generate the map, such as the seed for the random source
+// An unknown strategy or number placement is an error, as the map would not be generated the way it was requested
+// The generation is stopped after the GenerationTimeout
+func GetGenerationOptionsFromRequest(c echo.Context) (mapgen.GenerationOptions, error) {
+	seed := extractInt64ParamOrDefault(c, "seed", 0)
+	strategy, ok := mapgen.ParseStrategy(c.QueryParam("strategy"))
+	if !ok {
+		names := make([]string, 0, len(mapgen.Strategies))
+		for _, strategy := range mapgen.Strategies {
+			names = append(names, string(strategy))
+		}
+		return mapgen.GenerationOptions{}, fmt.Errorf("the strategy %q must be one of %s", c.QueryParam("strategy"), strings.Join(names, ", "))
+	}
+
+	numbers, ok := mapgen.ParseNumberPlacement(c.QueryParam("numbers"))
+	if !ok {
+		return mapgen.GenerationOptions{}, fmt.Errorf("the numbers %q must be one of %s",
+			c.QueryParam("numbers"), strings.Join(sortedKeys(mapgen.NumberPlacements), ", "))
+	}
+
+	bestEffort := extractBoolParamOrDefault(c, "bestEffort", false)
+
+	options := mapgen.GenerationOptions{
+		Seed:       seed,
+		Strategy:   strategy,
+		Timeout:    GenerationTimeout,
+		BestEffort: bestEffort,
+		Numbers:    numbers,
+	}
+	return options, nil
+}
+
+// GetLocksFromRequest retrieves the locked tiles from the lock parameter, for the game type of the rules
+// Unlike the other parameters, invalid locks are an error, as a map that ignores them does not fit the board of the requester,
+// which includes locked numbers when the numbers of the options are placed in alphabetical order
+func GetLocksFromRequest(c echo.Context, rules game.GameRules, options mapgen.GenerationOptions) (game.Locks, error) {
+	_, definedGame := game.DefinedGames.ForRules(rules)
+	locks, err := game.ParseLocks(c.QueryParam("lock"), definedGame.GameType)
+	if err != nil {
+		return nil, err
+	}
+	if err := mapgen.VerifyNumberLocks(options.Numbers, locks); err != nil {
+		return nil, err
+	}
+	return locks, nil
+}
+
+// GetOptimizationOptionsFromRequest retrieves the objective and the number of iterations to optimize the map with
+// An unknown objective is an error, which names the objectives that exist
+func GetOptimizationOptionsFromRequest(c echo.Context) (*mapgen.OptimizationOptions, error) {
+	objective, ok := mapgen.ParseObjective(c.QueryParam("objective"))
+	if !ok {
+		names := make([]string, 0, len(mapgen.Objectives))
+		for _, objective := range mapgen.Objectives {
+			names = append(names, string(objective))
+		}
+		return nil, fmt.Errorf("the objective %q must be one of %s", c.QueryParam("objective"), strings.Join(names, ", "))
+	}
+	iterations := extractIntParamOrDefault(c, "iterations", mapgen.DefaultOptimizationIterations)
+	if iterations <= 0 || iterations > maxOptimizationIterations {
+		iterations = mapgen.DefaultOptimizationIterations
+	}
+
+	options := &mapgen.OptimizationOptions{
+		Objective:  objective,
+		Iterations: iterations,
+	}
+	return options, nil
+}
+
+// GetSimulationOptionsFromRequest retrieves the number of games and turns to simulate, and whether to move the robber
+// A number of games or turns that is not positive or above the maximum falls back to the default
+func GetSimulationOptionsFromRequest(c echo.Context) analysis.SimulationOptions {
+	games := extractIntParamOrDefault(c, "games", analysis.DefaultSimulationGames)
+	if games <= 0 || games > maxSimulationGames {
+		games = analysis.DefaultSimulationGames
+	}
+	turns := extractIntParamOrDefault(c, "turns", analysis.DefaultSimulationTurns)
+	if turns <= 0 || turns > maxSimulationTurns {
+		turns = analysis.DefaultSimulationTurns
+	}
+
+	options := analysis.SimulationOptions{
+		Games:  games,
+		Turns:  turns,
+		Robber: extractBoolParamOrDefault(c, "robber", false),
+	}
+	return options
+}
+
 func GetRequestInfoFromRequest(c echo.Context) model.RequestInfo {
 	callback := c.QueryParam("callback")
 	jsonpInput := c.QueryParam("jsonp")
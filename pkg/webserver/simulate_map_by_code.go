@@ -0,0 +1,53 @@
+package webserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/analysis"
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/mapgen"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// SimulateMapByCode rolls the dice over the board of a game code, and reports the spread of the yields
+// The games and turns parameters are how many games of how many turns to simulate, the robber parameter
+// moves the robber on a 7, and the seed parameter reproduces an earlier simulation
+// The simulation stops after the GenerationTimeout, and reports the games that were simulated by then
+func SimulateMapByCode(c echo.Context) error {
+	code := c.Param("code")
+	requestInfo := GetRequestInfoFromRequest(c)
+
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(c, code, err, requestInfo.JSONP, requestInfo.Callback, func(message string) interface{} {
+			content := model.Simulation{Error: message}
+			content.GameCode = sanitize.Name(code)
+			return &content
+		})
+	}
+
+	options := GetSimulationOptionsFromRequest(c)
+	random, seed := mapgen.NewRandom(extractInt64ParamOrDefault(c, "seed", 0))
+	ctx, cancel := context.WithTimeout(c.Request().Context(), GenerationTimeout)
+	defer cancel()
+	content := model.Simulation{
+		Simulation: analysis.Simulate(ctx, &board, options, random),
+		Seed:       seed,
+	}
+	log.WithFields(log.Fields{
+		"RequestId": requestInfo.RequestId,
+		"Code":      sanitize.Name(code),
+		"Seed":      seed,
+		"Games":     content.Games,
+		"Turns":     content.Turns,
+	}).Info("Simulated a map")
+
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &content)
+	}
+	return c.JSON(http.StatusOK, &content)
+}
@@ -0,0 +1,54 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/analysis"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func simulateCode(t *testing.T, code string, query string) (int, model.Simulation) {
+	targetPath := fmt.Sprintf("%v/map/code/%v/simulate%v", baseApiPath, code, query)
+
+	var simulation model.Simulation
+	status := callByCode(t, SimulateMapByCode, targetPath, code, &simulation)
+	return status, simulation
+}
+
+func TestSimulateMapByCode(t *testing.T) {
+	code := testGameCode
+	status, simulation := simulateCode(t, code, "?games=100&turns=30&robber=true&seed=12")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, simulation.Error)
+	assert.Equal(t, code, simulation.GameCode)
+	assert.Equal(t, int64(12), simulation.Seed)
+	assert.Equal(t, 100, simulation.Games)
+	assert.Equal(t, 30, simulation.Turns)
+	assert.True(t, simulation.Robber)
+	assert.Len(t, simulation.Tiles, 18)
+
+	_, reproduced := simulateCode(t, code, "?games=100&turns=30&robber=true&seed=12")
+	assert.Equal(t, simulation, reproduced)
+}
+
+func TestSimulateMapByCodeFallsBackToDefaults(t *testing.T) {
+	code := testGameCode
+	status, simulation := simulateCode(t, code, "?games=-1&turns=100000")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotZero(t, simulation.Seed)
+	assert.Equal(t, analysis.DefaultSimulationGames, simulation.Games)
+	assert.Equal(t, analysis.DefaultSimulationTurns, simulation.Turns)
+}
+
+func TestSimulateMapByCodeIsUnrecognizable(t *testing.T) {
+	status, simulation := simulateCode(t, "abc", "")
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", simulation.Error)
+	assert.Empty(t, simulation.Tiles)
+}
@@ -0,0 +1,51 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/kennygrant/sanitize"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// ValidateMapByCode validates the board of a game code against the Game Rules, and reports the result of every rule
+// The rules are the defaults of the game type of the code, unless the type parameter is supplied
+// The other query parameters are the same as for generating a map
+func ValidateMapByCode(c echo.Context) error {
+	code := c.Param("code")
+	requestInfo := GetRequestInfoFromRequest(c)
+
+	board, err := game.InflateGameFromCode(code)
+	if err != nil {
+		return respondInvalidGameCode(c, code, err, requestInfo.JSONP, requestInfo.Callback, func(message string) interface{} {
+			return &model.Validation{GameCode: sanitize.Name(code), Error: message}
+		})
+	}
+
+	gameTypeParam := c.QueryParam("type")
+	if gameTypeParam == "" {
+		gameTypeParam, _ = game.DefinedGames.KeyOf(board.GameType)
+	}
+	rules := getGameRulesForGameType(c, gameTypeParam)
+	report := board.Validate(rules)
+
+	log.WithFields(log.Fields{
+		"RequestId": requestInfo.RequestId,
+		"Code":      sanitize.Name(code),
+		"Valid":     report.Valid,
+		"Failures":  len(report.Failures()),
+	}).Info("Validated a map")
+
+	content := model.Validation{
+		GameType: board.GameType.Name,
+		GameCode: code,
+		Valid:    report.Valid,
+		Results:  report.Results,
+	}
+	if requestInfo.JSONP {
+		return c.JSONP(http.StatusOK, requestInfo.Callback, &content)
+	}
+	return c.JSON(http.StatusOK, &content)
+}
@@ -0,0 +1,77 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/joostvdg/cmg/pkg/game"
+	"github.com/joostvdg/cmg/pkg/webserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var validateApiPath = "map/validate"
+
+func validateCode(t *testing.T, code string, query string) (int, model.Validation) {
+	targetPath := fmt.Sprintf("%v/%v/%v%v", baseApiPath, validateApiPath, code, query)
+
+	var validation model.Validation
+	status := callByCode(t, ValidateMapByCode, targetPath, code, &validation)
+	return status, validation
+}
+
+func TestValidateCodeReportsEveryRule(t *testing.T) {
+	code := testGameCode
+	status, validation := validateCode(t, code, "")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, game.NormalGame.Name, validation.GameType)
+	assert.Equal(t, code, validation.GameCode)
+	assert.Empty(t, validation.Error)
+	assert.Equal(t, len(game.Validations), len(validation.Results))
+}
+
+func TestValidateCodeWithFailingRule(t *testing.T) {
+	code := testGameCode
+	status, validation := validateCode(t, code, "?noAdjacentSameNumber=true")
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.False(t, validation.Valid)
+	failed := false
+	for _, result := range validation.Results {
+		if result.Rule == "SameNumbers" {
+			failed = !result.Valid
+			assert.Equal(t, []string{"b3", "c1", "c4", "d0"}, result.Positions)
+		}
+	}
+	assert.True(t, failed)
+}
+
+func TestValidateCodeIsUnrecognizable(t *testing.T) {
+	status, validation := validateCode(t, "abc", "")
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Could not inflate map base on game code abc, reason: Unrecognizable game code", validation.Error)
+	assert.Empty(t, validation.Results)
+}
+
+func seatGapResult(validation model.Validation) game.ValidationResult {
+	for _, result := range validation.Results {
+		if result.Rule == "SeatGap" {
+			return result
+		}
+	}
+	return game.ValidationResult{}
+}
+
+func TestValidateCodeWithSeatGap(t *testing.T) {
+	code := testGameCode
+	_, fourPlayers := validateCode(t, code, "?maxSeatGap=3")
+	assert.True(t, seatGapResult(fourPlayers).Valid)
+
+	status, fivePlayers := validateCode(t, code, "?maxSeatGap=3&players=5")
+	assert.Equal(t, http.StatusOK, status)
+	assert.False(t, fivePlayers.Valid)
+	assert.False(t, seatGapResult(fivePlayers).Valid)
+	assert.Equal(t, 4, seatGapResult(fivePlayers).Value)
+}